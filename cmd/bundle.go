@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/germainlefebvre4/kuve/internal/kubectl"
+	"github.com/germainlefebvre4/kuve/internal/version"
+	"github.com/germainlefebvre4/kuve/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	bundleOutput    string
+	bundlePlatforms string
+)
+
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Create and install air-gap bundles",
+	Long: `Carry a set of kubectl versions into disconnected environments.
+
+A bundle is a .tar.gz archive holding kubectl binaries for one or more
+versions and platforms, their checksums and a manifest.`,
+}
+
+var bundleCreateCmd = &cobra.Command{
+	Use:   "create <version> [version...]",
+	Short: "Create a bundle of kubectl versions",
+	Long: `Download kubectl versions for the given platforms, verify them against
+the upstream checksums and write them to a bundle.
+
+Example:
+  kuve bundle create -o bundle.tar.gz 1.27 1.28
+  kuve bundle create -o bundle.tar.gz 1.28.3 --platform linux/amd64,linux/arm64`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireKubectl("bundle create"); err != nil {
+			return err
+		}
+
+		cfg, err := config.New()
+		if err != nil {
+			return fmt.Errorf("failed to create config: %w", err)
+		}
+
+		platforms, err := kubectl.ParsePlatforms(bundlePlatforms)
+		if err != nil {
+			return err
+		}
+		if len(platforms) == 0 {
+			platforms = []kubectl.Platform{kubectl.CurrentPlatform()}
+		}
+
+		manager := version.NewManager(cfg)
+		installer := kubectl.NewInstaller(cfg)
+		installer.Quiet = quiet
+		installer.Out = messages()
+
+		var versions []string
+		seen := map[string]bool{}
+		for _, arg := range args {
+			resolvedVersion, err := manager.ResolveVersion(cmd.Context(), arg)
+			if err != nil {
+				return err
+			}
+			if !seen[resolvedVersion] {
+				seen[resolvedVersion] = true
+				versions = append(versions, resolvedVersion)
+			}
+		}
+
+		manifest, err := installer.CreateBundle(cmd.Context(), bundleOutput, versions, platforms)
+		if err != nil {
+			return err
+		}
+
+		out := messages()
+		result := bundleCreateResult{File: bundleOutput, Entries: []bundleEntry{}}
+		fmt.Fprintf(out, "Created %s with %d kubectl binaries:\n", bundleOutput, len(manifest.Entries))
+		for _, entry := range manifest.Entries {
+			fmt.Fprintf(out, "  %s %s\n", entry.Version, entry.Platform)
+			result.Entries = append(result.Entries, bundleEntry{Version: entry.Version, Platform: entry.Platform.String(), SHA256: entry.SHA256})
+		}
+
+		return printResult(result)
+	},
+}
+
+var bundleInstallCmd = &cobra.Command{
+	Use:   "install <bundle>",
+	Short: "Install kubectl versions from a bundle",
+	Long: `Verify and install the kubectl versions of a bundle matching the local
+platform. Versions that are already installed are skipped.
+
+Example:
+  kuve bundle install bundle.tar.gz`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireKubectl("bundle install"); err != nil {
+			return err
+		}
+
+		cfg, err := config.New()
+		if err != nil {
+			return fmt.Errorf("failed to create config: %w", err)
+		}
+
+		// Ensure directories exist
+		if err := cfg.EnsureDirectories(); err != nil {
+			return fmt.Errorf("failed to create directories: %w", err)
+		}
+
+		installer := kubectl.NewInstaller(cfg)
+		installer.Out = messages()
+		platform := kubectl.CurrentPlatform()
+
+		results, err := installer.InstallBundle(cmd.Context(), args[0], platform)
+		if err != nil {
+			return err
+		}
+
+		out := messages()
+		result := bundleInstallResult{File: args[0], Platform: platform.String(), Versions: []installStatus{}}
+		failed := 0
+		var firstErr error
+		for _, r := range results {
+			switch {
+			case r.Err != nil:
+				failed++
+				if firstErr == nil {
+					firstErr = r.Err
+				}
+				fmt.Fprintf(out, "  %s: %v\n", r.Version, r.Err)
+				result.Versions = append(result.Versions, installStatus{Version: r.Version, Status: statusFailed, Error: r.Err.Error()})
+			case r.Skipped:
+				fmt.Fprintf(out, "  %s: already installed\n", r.Version)
+				result.Versions = append(result.Versions, installStatus{Version: r.Version, Status: statusAlreadyInstalled})
+			default:
+				fmt.Fprintf(out, "  %s: installed\n", r.Version)
+				result.Versions = append(result.Versions, installStatus{Version: r.Version, Status: statusInstalled})
+			}
+		}
+
+		if err := printResult(result); err != nil {
+			return err
+		}
+		if failed > 0 {
+			// Keep the first failure so that the exit code reports its cause
+			return fmt.Errorf("%d version(s) failed to install from bundle: %w", failed, firstErr)
+		}
+
+		fmt.Fprintf(out, "Installed bundle %s for %s\n", args[0], platform)
+		return nil
+	},
+}
+
+func init() {
+	bundleCreateCmd.Flags().StringVarP(&bundleOutput, "output-file", "o", "kuve-bundle.tar.gz", "path of the bundle to create")
+	bundleCreateCmd.Flags().StringVar(&bundlePlatforms, "platform", "", "comma separated list of os/arch platforms (default: current platform)")
+	rootCmd.AddCommand(bundleCmd)
+	bundleCmd.AddCommand(bundleCreateCmd)
+	bundleCmd.AddCommand(bundleInstallCmd)
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/germainlefebvre4/kuve/internal/kubectl"
+	"github.com/germainlefebvre4/kuve/internal/version"
+)
+
+// Exit codes of kuve, documented in docs/cli-reference.md
+const (
+	exitOK               = 0
+	exitError            = 1
+	exitNotInstalled     = 2
+	exitAlreadyInstalled = 3 // kuve import only, install is idempotent
+	exitNotFound         = 4
+	exitNetwork          = 5
+	exitChecksumMismatch = 6
+	exitNoVersionFile    = 7
+	exitInterrupted      = 130
+)
+
+// exitCodes maps the classified errors to their exit code, most specific first:
+// a checksum mismatch is reported as such even when a download also failed
+var exitCodes = []struct {
+	err  error
+	code int
+}{
+	{kubectl.ErrChecksumMismatch, exitChecksumMismatch},
+	{kubectl.ErrNotInstalled, exitNotInstalled},
+	{kubectl.ErrAlreadyInstalled, exitAlreadyInstalled},
+	{version.ErrNoVersionFile, exitNoVersionFile},
+	{kubectl.ErrNotFound, exitNotFound},
+	{version.ErrNotFound, exitNotFound},
+	{kubectl.ErrNetwork, exitNetwork},
+	{version.ErrNetwork, exitNetwork},
+}
+
+// exitCode returns the exit code reporting an error
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+	for _, entry := range exitCodes {
+		if errors.Is(err, entry.err) {
+			return entry.code
+		}
+	}
+	return exitError
+}
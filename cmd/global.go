@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/germainlefebvre4/kuve/internal/version"
+	"github.com/germainlefebvre4/kuve/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var globalUnset bool
+
+var globalCmd = &cobra.Command{
+	Use:   "global [version]",
+	Short: "Show or set the global kubectl version",
+	Long: `Show or set the global kubectl version, the default used by 'kuve use'
+(and the shell hooks running it) wherever no version file applies.
+
+The global version is stored in the configuration file (~/.kuve/config.yaml)
+under "global", one entry per tool. It does not change the active version:
+run 'kuve use' to switch to it.
+
+Version selection, highest precedence first:
+  1. KUVE_KUBECTL_VERSION environment variable (KUVE_<TOOL>_VERSION with --tool)
+  2. .kubernetes-version file in the current or a parent directory ('kuve local')
+  3. global version ('kuve global')
+
+Example:
+  kuve global             # show the global version
+  kuve global 1.29.3      # set it
+  kuve global 1.29        # set it to the latest 1.29 patch release
+  kuve global --unset     # remove it`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		tool := cfg.Tool()
+
+		if globalUnset {
+			if len(args) > 0 {
+				return fmt.Errorf("--unset does not take a version")
+			}
+			if err := cfg.SetGlobalVersion(""); err != nil {
+				return err
+			}
+			fmt.Fprintf(messages(), "Removed the global %s version\n", tool.Name)
+			return printResult(currentResult{Tool: tool.Name})
+		}
+
+		if len(args) == 0 {
+			globalVersion := cfg.GlobalVersion()
+			if structuredOutput() {
+				return printResult(currentResult{Tool: tool.Name, Version: globalVersion})
+			}
+			if globalVersion == "" {
+				fmt.Printf("No global %s version set. Use 'kuve global <version>' to set one.\n", tool.Name)
+				return nil
+			}
+			fmt.Printf("Global %s version: %s\n", tool.Name, globalVersion)
+			return nil
+		}
+
+		// Resolve partial versions (1.29, latest) to the exact version saved
+		manager := version.NewManager(cfg)
+		globalVersion := normalizeVersion(args[0])
+		if !config.IsFullVersion(globalVersion) {
+			globalVersion, err = manager.ResolveVersion(cmd.Context(), args[0])
+			if err != nil {
+				return fmt.Errorf("failed to resolve version: %w", err)
+			}
+		}
+
+		if err := cfg.SetGlobalVersion(globalVersion); err != nil {
+			return err
+		}
+
+		out := messages()
+		fmt.Fprintf(out, "Set the global %s version to %s in %s\n", tool.Name, globalVersion, cfg.ConfigFile)
+		if !manager.IsVersionInstalled(globalVersion) {
+			fmt.Fprintf(out, "Note: %s is not installed yet, 'kuve use' will install it\n", globalVersion)
+		}
+		return printResult(currentResult{Tool: tool.Name, Version: globalVersion})
+	},
+}
+
+func init() {
+	globalCmd.Flags().BoolVar(&globalUnset, "unset", false, "remove the global version")
+	rootCmd.AddCommand(globalCmd)
+}
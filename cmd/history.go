@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/germainlefebvre4/kuve/internal/kubectl"
+	"github.com/germainlefebvre4/kuve/internal/version"
+	"github.com/germainlefebvre4/kuve/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyLimit int
+	historyAll   bool
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show the history of version switches",
+	Long: `Show the recent switches of the active kubectl version, oldest first,
+with the version switched from and what triggered the switch:
+
+  manual   kuve switch
+  use      kuve use with a version file
+  hook     kuve use --hook, run by a shell hook
+  cluster  kuve use --from-cluster
+
+With --tool, the switches of that tool are shown; --all shows every tool.
+'kuve switch -' returns to the version active before the last switch.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
+		history, err := version.ReadHistory(cfg)
+		if err != nil {
+			return err
+		}
+
+		entries := []version.HistoryEntry{}
+		for _, entry := range history {
+			if historyAll || entry.Tool == cfg.Tool().Name {
+				entries = append(entries, entry)
+			}
+		}
+		if historyLimit > 0 && len(entries) > historyLimit {
+			entries = entries[len(entries)-historyLimit:]
+		}
+
+		if structuredOutput() {
+			return printResult(entries)
+		}
+
+		if len(entries) == 0 {
+			fmt.Println("No version switches recorded yet.")
+			return nil
+		}
+
+		for _, entry := range entries {
+			from := entry.From
+			if from == "" {
+				from = "(none)"
+			}
+			fmt.Printf("%s  %-8s %-8s %s -> %s\n", entry.Time.Local().Format(time.DateTime),
+				entry.Tool, entry.Trigger, from, entry.To)
+		}
+		return nil
+	},
+}
+
+// switchVersion switches to a version and records the switch in the history.
+// Switching to the version already active is not recorded.
+func switchVersion(cfg *config.Config, manager *version.Manager, installer *kubectl.Installer, to, trigger string) error {
+	from, _ := manager.GetCurrentVersion()
+	if err := installer.Switch(to); err != nil {
+		return err
+	}
+	if from == to {
+		return nil
+	}
+
+	entry := version.HistoryEntry{
+		Time:    time.Now().UTC(),
+		Tool:    cfg.Tool().Name,
+		From:    from,
+		To:      to,
+		Trigger: trigger,
+	}
+	if err := version.AppendHistory(cfg, entry); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	return nil
+}
+
+func init() {
+	historyCmd.Flags().IntVarP(&historyLimit, "limit", "n", 20, "number of switches to show (0 for all)")
+	historyCmd.Flags().BoolVar(&historyAll, "all", false, "show the switches of all tools")
+	rootCmd.AddCommand(historyCmd)
+}
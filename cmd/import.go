@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/germainlefebvre4/kuve/internal/kubectl"
+	"github.com/germainlefebvre4/kuve/internal/version"
+	"github.com/germainlefebvre4/kuve/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importVersion string
+	importSHA256  string
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <path>",
+	Short: "Install kubectl from a local file",
+	Long: `Install a kubectl binary or an official kubernetes-client tarball from the
+local file system, for environments without access to dl.k8s.io.
+
+The version is detected by running 'kubectl version --client'. Use --version
+when the binary cannot run on this machine, or to confirm the expected version.
+
+Example:
+  kuve import ./kubectl
+  kuve import kubernetes-client-linux-amd64.tar.gz --version v1.28.3
+  kuve import ./kubectl --sha256 <checksum>`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireKubectl("import"); err != nil {
+			return err
+		}
+
+		cfg, err := config.New()
+		if err != nil {
+			return fmt.Errorf("failed to create config: %w", err)
+		}
+
+		// Ensure directories exist
+		if err := cfg.EnsureDirectories(); err != nil {
+			return fmt.Errorf("failed to create directories: %w", err)
+		}
+
+		installer := kubectl.NewInstaller(cfg)
+		installer.Out = messages()
+		imported, err := installer.Import(cmd.Context(), args[0], kubectl.ImportOptions{
+			Version: importVersion,
+			SHA256:  importSHA256,
+		})
+		if err != nil {
+			return err
+		}
+
+		return printResult(importResult{
+			Tool:    cfg.Tool().Name,
+			Version: imported,
+			Source:  args[0],
+			Path:    version.NewManager(cfg).BinaryPath(imported),
+		})
+	},
+}
+
+func init() {
+	importCmd.Flags().StringVar(&importVersion, "version", "", "version of the imported kubectl (detected when omitted)")
+	importCmd.Flags().StringVar(&importSHA256, "sha256", "", "expected SHA-256 checksum of the imported file")
+	rootCmd.AddCommand(importCmd)
+}
@@ -4,25 +4,60 @@ import (
 	"fmt"
 
 	"github.com/germainlefebvre4/kuve/internal/kubectl"
-	"github.com/germainlefebvre4/kuve/pkg/config"
+	"github.com/germainlefebvre4/kuve/internal/version"
 	"github.com/spf13/cobra"
 )
 
+var (
+	installConcurrency int
+	installOS          string
+	installArch        string
+	installDir         string
+	installWith        []string
+	installForce       bool
+
+	// verifySignature requires valid cosign signatures (install and use)
+	verifySignature bool
+)
+
 var installCmd = &cobra.Command{
-	Use:   "install <version>",
-	Short: "Install a specific kubectl version",
-	Long: `Download and install a specific kubectl version.
+	Use:   "install <version> [version...]",
+	Short: "Install one or more kubectl versions",
+	Long: `Download and install one or more kubectl versions.
+
+A minor version (e.g. 1.28) installs its latest patch release.
+Several versions are downloaded concurrently; a failing version
+does not stop the others.
+
+With --os and --arch, binaries for another platform are downloaded into
+~/.kuve/platforms/<os>-<arch>/<version>/, or into <dir>/<version>/ with
+--dir. Such installs never change the active version.
+
+With --with, companion binaries (kubectl-convert, kubeadm) of the same
+version are installed next to kubectl and linked into the bin directory
+when the version is active. Missing companions are added to versions
+that are already installed.
+
+With --verify-signature (or signature.verify in the configuration), the
+cosign keyless signature (.sig/.cert) of each kubectl artifact must be
+signed by the Kubernetes release identity.
+
+Versions that are already installed are reported and left untouched, so
+the command can be rerun safely. With --force, their binaries are
+downloaded again and replace the installed ones once all downloads
+succeeded.
 
 Example:
   kuve install v1.28.0
-  kuve install 1.28.0`,
-	Args: cobra.ExactArgs(1),
+  kuve install 1.28.0
+  kuve install 1.27 1.28 1.29.3
+  kuve install 1.28 --os linux --arch arm64 --dir ./out
+  kuve install 1.28 --with kubectl-convert,kubeadm`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		version := args[0]
-
-		cfg, err := config.New()
+		cfg, err := loadConfig()
 		if err != nil {
-			return fmt.Errorf("failed to create config: %w", err)
+			return err
 		}
 
 		// Ensure directories exist
@@ -30,15 +65,101 @@ Example:
 			return fmt.Errorf("failed to create directories: %w", err)
 		}
 
+		manager := version.NewManager(cfg)
 		installer := kubectl.NewInstaller(cfg)
-		if err := installer.Install(version); err != nil {
+		installer.Quiet = quiet
+		installer.Out = messages()
+		if installOS != "" {
+			installer.Platform.OS = installOS
+		}
+		if installArch != "" {
+			installer.Platform.Arch = installArch
+		}
+		installer.TargetDir = installDir
+		installer.With = installWith
+		installer.Force = installForce
+		if verifySignature {
+			if err := requireKubectl("install --verify-signature"); err != nil {
+				return err
+			}
+			installer.VerifySignature = true
+		}
+		if err := kubectl.ValidateCompanions(installWith); err != nil {
 			return err
 		}
 
+		result := installResult{Tool: cfg.Tool().Name, Platform: installer.Platform.String()}
+
+		if len(args) == 1 {
+			resolvedVersion, err := manager.ResolveVersion(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			r := installer.InstallMany(cmd.Context(), []string{resolvedVersion}, 1)[0]
+			if r.Err != nil {
+				return r.Err
+			}
+			result.Versions = append(result.Versions, newInstallStatus(r))
+			return printResult(result)
+		}
+
+		// Resolve all requested versions, skipping duplicates
+		var failed []kubectl.InstallResult
+		var versions []string
+		seen := map[string]bool{}
+		for _, arg := range args {
+			resolvedVersion, err := manager.ResolveVersion(cmd.Context(), arg)
+			if err != nil {
+				failed = append(failed, kubectl.InstallResult{Version: arg, Err: err})
+				continue
+			}
+			if !seen[resolvedVersion] {
+				seen[resolvedVersion] = true
+				versions = append(versions, resolvedVersion)
+			}
+		}
+
+		results := installer.InstallMany(cmd.Context(), versions, installConcurrency)
+
+		succeeded := 0
+		for _, r := range results {
+			if r.Err != nil {
+				failed = append(failed, r)
+				continue
+			}
+			succeeded++
+			result.Versions = append(result.Versions, newInstallStatus(r))
+		}
+		for _, r := range failed {
+			result.Versions = append(result.Versions, installStatus{Version: r.Version, Status: statusFailed, Error: r.Err.Error()})
+		}
+
+		out := messages()
+		fmt.Fprintf(out, "\nInstalled %d of %d versions\n", succeeded, succeeded+len(failed))
+		for _, r := range failed {
+			fmt.Fprintf(out, "  %s: %v\n", r.Version, r.Err)
+		}
+
+		if err := printResult(result); err != nil {
+			return err
+		}
+
+		if len(failed) > 0 {
+			// Keep the first failure so that the exit code reports its cause
+			return fmt.Errorf("%d version(s) failed to install: %w", len(failed), failed[0].Err)
+		}
+
 		return nil
 	},
 }
 
 func init() {
+	installCmd.Flags().IntVarP(&installConcurrency, "concurrency", "j", kubectl.DefaultConcurrency, "maximum number of parallel downloads")
+	installCmd.Flags().StringVar(&installOS, "os", "", "target operating system (default: current OS)")
+	installCmd.Flags().StringVar(&installArch, "arch", "", "target architecture (default: current architecture)")
+	installCmd.Flags().StringVar(&installDir, "dir", "", "install into <dir>/<version>/ instead of the kuve store")
+	installCmd.Flags().BoolVar(&verifySignature, "verify-signature", false, "require a valid cosign signature of the kubectl artifacts")
+	installCmd.Flags().BoolVarP(&installForce, "force", "f", false, "download installed versions again and replace their binaries")
+	installCmd.Flags().StringSliceVar(&installWith, "with", nil, "companion binaries to install (kubectl-convert, kubeadm)")
 	rootCmd.AddCommand(installCmd)
 }
@@ -2,16 +2,18 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/germainlefebvre4/kuve/internal/version"
-	"github.com/germainlefebvre4/kuve/pkg/config"
 	"github.com/spf13/cobra"
 )
 
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List kubectl versions",
-	Long:  `List available kubectl versions (remote or installed).`,
+	Long: `List available kubectl versions (remote or installed).
+
+With --tool, the versions of that tool are listed instead.`,
 }
 
 var listRemoteCmd = &cobra.Command{
@@ -19,29 +21,33 @@ var listRemoteCmd = &cobra.Command{
 	Short: "List available remote kubectl versions",
 	Long:  `List all available kubectl versions that can be downloaded.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, err := config.New()
+		cfg, err := loadConfig()
 		if err != nil {
-			return fmt.Errorf("failed to create config: %w", err)
+			return err
 		}
 
 		manager := version.NewManager(cfg)
 
-		remoteVersions, err := manager.ListRemoteVersions()
+		remoteVersions, err := manager.ListRemoteVersions(cmd.Context())
 		if err != nil {
 			return fmt.Errorf("failed to list remote versions: %w", err)
 		}
 
+		if structuredOutput() {
+			return printResult(remoteList{Tool: cfg.Tool().Name, Versions: nonNil(remoteVersions)})
+		}
+
 		if len(remoteVersions) == 0 {
 			fmt.Println("No remote versions available.")
 			return nil
 		}
 
-		fmt.Println("Last 10 stable kubectl versions:")
+		fmt.Printf("Last 10 stable %s versions:\n", cfg.Tool().Name)
 		for _, v := range remoteVersions {
 			fmt.Printf("  %s\n", v)
 		}
 
-		fmt.Println("\nNote: For a full list of versions, visit https://github.com/kubernetes/kubernetes/releases")
+		fmt.Printf("\nNote: For a full list of versions, visit https://github.com/%s/releases\n", cfg.Tool().GitHubRepo)
 
 		return nil
 	},
@@ -52,9 +58,9 @@ var listInstalledCmd = &cobra.Command{
 	Short: "List installed kubectl versions",
 	Long:  `List all kubectl versions installed on this system.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, err := config.New()
+		cfg, err := loadConfig()
 		if err != nil {
-			return fmt.Errorf("failed to create config: %w", err)
+			return err
 		}
 
 		manager := version.NewManager(cfg)
@@ -63,27 +69,52 @@ var listInstalledCmd = &cobra.Command{
 			return fmt.Errorf("failed to list installed versions: %w", err)
 		}
 
+		// Get current version
+		currentVersion, _ := manager.GetCurrentVersion()
+
+		if structuredOutput() {
+			result := installedList{Tool: cfg.Tool().Name, Current: currentVersion, Versions: []installedVersion{}}
+			for _, v := range versions {
+				result.Versions = append(result.Versions, installedVersion{
+					Version:    v,
+					Current:    v == currentVersion,
+					Pinned:     manager.IsVersionPinned(v),
+					Companions: nonNil(manager.InstalledCompanions(v)),
+				})
+			}
+			return printResult(result)
+		}
+
 		if len(versions) == 0 {
-			fmt.Println("No kubectl versions installed.")
+			fmt.Printf("No %s versions installed.\n", cfg.Tool().Name)
 			fmt.Println("Use 'kuve install <version>' to install a version.")
 			return nil
 		}
 
-		// Get current version
-		currentVersion, _ := manager.GetCurrentVersion()
-
-		fmt.Println("Installed kubectl versions:")
+		hasPinned := false
+		fmt.Printf("Installed %s versions:\n", cfg.Tool().Name)
 		for _, v := range versions {
 			marker := " "
 			if v == currentVersion {
 				marker = "*"
 			}
-			fmt.Printf("%s %s\n", marker, v)
+			line := fmt.Sprintf("%s %s", marker, v)
+			if manager.IsVersionPinned(v) {
+				hasPinned = true
+				line += " (pinned)"
+			}
+			if companions := manager.InstalledCompanions(v); len(companions) > 0 {
+				line += fmt.Sprintf(" [%s]", strings.Join(companions, ", "))
+			}
+			fmt.Println(line)
 		}
 
 		if currentVersion != "" {
 			fmt.Printf("\n* = current version (%s)\n", currentVersion)
 		}
+		if hasPinned {
+			fmt.Println("(pinned) = protected from uninstall, see 'kuve unpin'")
+		}
 
 		return nil
 	},
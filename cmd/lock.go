@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/germainlefebvre4/kuve/internal/kubectl"
+	"github.com/germainlefebvre4/kuve/internal/version"
+	"github.com/germainlefebvre4/kuve/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var lockPlatforms string
+
+var lockCmd = &cobra.Command{
+	Use:   "lock",
+	Short: "Pin the version file to exact binaries in .kuve.lock",
+	Long: `Resolve the version of the nearest .kubernetes-version file (or the version
+file of the tool selected with --tool) and record the resolved version and
+the SHA-256 checksums of its binary for each platform in a .kuve.lock file
+next to the version file.
+
+'kuve use' then installs the locked version and refuses binaries that do not
+match the locked checksum, so that every machine runs the identical artifact.
+Commit .kuve.lock together with the version file.
+
+Example:
+  kuve lock
+  kuve lock --platform linux/amd64,darwin/arm64,windows/amd64`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
+		platforms, err := kubectl.ParsePlatforms(lockPlatforms)
+		if err != nil {
+			return err
+		}
+		if len(platforms) == 0 {
+			platforms = []kubectl.Platform{kubectl.CurrentPlatform()}
+		}
+
+		tool := cfg.Tool()
+		versionDir, spec, err := version.LocateVersionFile(tool.VersionFile)
+		if err != nil {
+			return fmt.Errorf("error searching for version file: %w", err)
+		}
+		if spec == "" {
+			return fmt.Errorf("%w: %s in current or parent directories", version.ErrNoVersionFile, tool.VersionFile)
+		}
+
+		manager := version.NewManager(cfg)
+		resolvedVersion, err := manager.ResolveVersion(cmd.Context(), spec)
+		if err != nil {
+			return err
+		}
+		if err := config.ValidateVersion(resolvedVersion); err != nil {
+			return err
+		}
+
+		installer := kubectl.NewInstaller(cfg)
+		installer.Quiet = quiet
+		installer.Out = messages()
+
+		entry := version.LockEntry{
+			Spec:      spec,
+			Version:   resolvedVersion,
+			Checksums: map[string]string{},
+		}
+		for _, platform := range platforms {
+			checksum, err := installer.BinaryChecksum(cmd.Context(), resolvedVersion, platform)
+			if err != nil {
+				return fmt.Errorf("failed to get checksum of %s %s for %s: %w", tool.Name, resolvedVersion, platform, err)
+			}
+			entry.Checksums[platform.String()] = checksum
+		}
+
+		lock, err := version.ReadLockFile(versionDir)
+		if err != nil {
+			return err
+		}
+		if lock == nil {
+			lock = &version.LockFile{}
+		}
+		if lock.Tools == nil {
+			lock.Tools = map[string]version.LockEntry{}
+		}
+		lock.Tools[tool.Name] = entry
+
+		if err := version.WriteLockFile(versionDir, lock); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(messages(), "Locked %s %s for %d platform(s) in %s\n", tool.Name, resolvedVersion, len(platforms), config.LockFileName)
+		return printResult(lockResult{
+			Tool:      tool.Name,
+			Spec:      spec,
+			Version:   resolvedVersion,
+			File:      filepath.Join(versionDir, config.LockFileName),
+			Checksums: entry.Checksums,
+		})
+	},
+}
+
+// lockEntry returns the entry of a tool in a lock file, which may be nil
+func lockEntry(lock *version.LockFile, tool string) (version.LockEntry, bool) {
+	if lock == nil {
+		return version.LockEntry{}, false
+	}
+	entry, ok := lock.Tools[tool]
+	return entry, ok
+}
+
+// lockedVersion resolves the version of a version file with the .kuve.lock
+// next to it, and returns the locked version with its checksum for the
+// platform. The spec is returned as is when the tool is not locked.
+func lockedVersion(cfg *config.Config, selection *version.Selection, platform string) (string, string, error) {
+	lock, err := version.ReadLockFile(selection.Dir)
+	if err != nil {
+		return "", "", err
+	}
+	entry, ok := lockEntry(lock, cfg.Tool().Name)
+	if !ok {
+		return selection.Spec, "", nil
+	}
+	resolved, err := entry.Resolve(selection.Spec)
+	if err != nil {
+		return "", "", err
+	}
+	checksum, err := entry.Checksum(platform)
+	if err != nil {
+		return "", "", err
+	}
+	return resolved, checksum, nil
+}
+
+func init() {
+	lockCmd.Flags().StringVar(&lockPlatforms, "platform", "", "comma-separated os/arch platforms to lock (default: current platform)")
+	rootCmd.AddCommand(lockCmd)
+}
@@ -0,0 +1,399 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/germainlefebvre4/kuve/internal/kubectl"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats selected with --output
+const (
+	outputText = "text"
+	outputJSON = "json"
+	outputYAML = "yaml"
+)
+
+// outputFormat is the format of the command results (--output)
+var outputFormat string
+
+// validateOutput checks the --output flag before any command runs
+func validateOutput(cmd *cobra.Command, args []string) error {
+	switch outputFormat {
+	case outputText, outputJSON, outputYAML:
+		return nil
+	}
+	return fmt.Errorf("invalid output format %q, supported: %s, %s, %s", outputFormat, outputText, outputJSON, outputYAML)
+}
+
+// structuredOutput reports whether results are printed as JSON or YAML
+func structuredOutput() bool {
+	return outputFormat == outputJSON || outputFormat == outputYAML
+}
+
+// messages returns the writer of human-readable messages: stdout in text
+// mode, stderr with structured output so that stdout stays parseable
+func messages() io.Writer {
+	if structuredOutput() {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// printResult writes a command result to stdout in the selected structured
+// format. Nothing is printed in text mode, where commands report as they go.
+func printResult(result any) error {
+	switch outputFormat {
+	case outputJSON:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	case outputYAML:
+		encoder := yaml.NewEncoder(os.Stdout)
+		encoder.SetIndent(2)
+		defer encoder.Close()
+		return encoder.Encode(result)
+	}
+	return nil
+}
+
+// nonNil returns an empty slice instead of nil, so that lists are never null
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// installedVersion is an entry of 'kuve list installed'
+type installedVersion struct {
+	Version    string   `json:"version" yaml:"version"`
+	Current    bool     `json:"current" yaml:"current"`
+	Pinned     bool     `json:"pinned" yaml:"pinned"`
+	Companions []string `json:"companions" yaml:"companions"`
+}
+
+// installedList is the result of 'kuve list installed'
+type installedList struct {
+	Tool     string             `json:"tool" yaml:"tool"`
+	Current  string             `json:"current" yaml:"current"`
+	Versions []installedVersion `json:"versions" yaml:"versions"`
+}
+
+// remoteList is the result of 'kuve list remote'
+type remoteList struct {
+	Tool     string   `json:"tool" yaml:"tool"`
+	Versions []string `json:"versions" yaml:"versions"`
+}
+
+// currentResult is the result of 'kuve current'
+type currentResult struct {
+	Tool    string `json:"tool" yaml:"tool"`
+	Version string `json:"version" yaml:"version"`
+}
+
+// installStatus is the outcome of the install of one version
+type installStatus struct {
+	Version string `json:"version" yaml:"version"`
+	Status  string `json:"status" yaml:"status"`
+	Error   string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// Install statuses
+const (
+	statusInstalled        = "installed"
+	statusAlreadyInstalled = "already-installed"
+	statusFailed           = "failed"
+)
+
+// newInstallStatus returns the status of a successful install
+func newInstallStatus(r kubectl.InstallResult) installStatus {
+	if r.AlreadyInstalled {
+		return installStatus{Version: r.Version, Status: statusAlreadyInstalled}
+	}
+	return installStatus{Version: r.Version, Status: statusInstalled}
+}
+
+// installResult is the result of 'kuve install'
+type installResult struct {
+	Tool     string          `json:"tool" yaml:"tool"`
+	Platform string          `json:"platform" yaml:"platform"`
+	Versions []installStatus `json:"versions" yaml:"versions"`
+}
+
+// switchResult is the result of 'kuve switch'
+type switchResult struct {
+	Tool    string `json:"tool" yaml:"tool"`
+	Version string `json:"version" yaml:"version"`
+	Path    string `json:"path" yaml:"path"`
+}
+
+// clusterResult is the version detected from the current cluster
+type clusterResult struct {
+	// ServerVersion is the version reported by the API server
+	ServerVersion string `json:"serverVersion" yaml:"serverVersion"`
+
+	// Version is the matching client version
+	Version string `json:"version" yaml:"version"`
+}
+
+// useResult is the result of 'kuve use'
+type useResult struct {
+	Tool    string `json:"tool" yaml:"tool"`
+	Version string `json:"version" yaml:"version"`
+
+	// Source is where the version comes from: "env", "file", "context", "global" or "cluster"
+	Source string `json:"source" yaml:"source"`
+
+	// File is the project file, with sources "file" and "context"
+	File string `json:"file,omitempty" yaml:"file,omitempty"`
+
+	// Context is the kubeconfig context, with source "context"
+	Context string `json:"context,omitempty" yaml:"context,omitempty"`
+
+	// Origin is the environment variable (source "env") or the configuration
+	// file (source "global") holding the version
+	Origin string `json:"origin,omitempty" yaml:"origin,omitempty"`
+
+	// Cluster is the detected cluster version, with source "cluster"
+	Cluster *clusterResult `json:"cluster,omitempty" yaml:"cluster,omitempty"`
+
+	// Installed reports whether the version had to be installed
+	Installed bool `json:"installed" yaml:"installed"`
+
+	// Locked reports whether the binary was verified against .kuve.lock
+	Locked bool `json:"locked" yaml:"locked"`
+
+	Path string `json:"path" yaml:"path"`
+}
+
+// statusResult is the result of 'kuve status'
+type statusResult struct {
+	Tool string `json:"tool" yaml:"tool"`
+
+	// Version is the effective version, empty when no version applies
+	Version string `json:"version" yaml:"version"`
+
+	// Source is where the version comes from: "env", "file", "context",
+	// "global" or "none" when no version applies
+	Source string `json:"source" yaml:"source"`
+
+	// Origin is the environment variable, project file or configuration file
+	// holding the version
+	Origin string `json:"origin,omitempty" yaml:"origin,omitempty"`
+
+	// Constraint is the version range of the project file the version was resolved from
+	Constraint string `json:"constraint,omitempty" yaml:"constraint,omitempty"`
+
+	// Context is the kubeconfig context, with source "context"
+	Context string `json:"context,omitempty" yaml:"context,omitempty"`
+
+	// Locked reports whether the version was resolved from .kuve.lock
+	Locked bool `json:"locked" yaml:"locked"`
+
+	Installed bool `json:"installed" yaml:"installed"`
+
+	// Binary is the binary of the effective version
+	Binary string `json:"binary,omitempty" yaml:"binary,omitempty"`
+
+	// Active is the version the symlink points to, empty when none
+	Active string `json:"active" yaml:"active"`
+
+	// Matches reports whether the symlink points to the effective version
+	Matches bool `json:"matches" yaml:"matches"`
+
+	Path string `json:"path" yaml:"path"`
+
+	// Cluster is the skew against the current cluster, for kubectl only
+	Cluster *clusterStatus `json:"cluster,omitempty" yaml:"cluster,omitempty"`
+}
+
+// clusterStatus is the version skew between kubectl and the current cluster
+type clusterStatus struct {
+	// Reachable reports whether the cluster version could be detected
+	Reachable bool `json:"reachable" yaml:"reachable"`
+
+	// ServerVersion is the version reported by the API server
+	ServerVersion string `json:"serverVersion,omitempty" yaml:"serverVersion,omitempty"`
+
+	// Skew is the number of minor versions kubectl is ahead of the server,
+	// negative when it is behind
+	Skew int `json:"skew" yaml:"skew"`
+
+	// Supported reports whether the skew is within the supported policy
+	Supported bool `json:"supported" yaml:"supported"`
+
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// whichResult is the result of 'kuve which'
+type whichResult struct {
+	Tool    string `json:"tool" yaml:"tool"`
+	Version string `json:"version" yaml:"version"`
+
+	// Source is where the version comes from: "env", "file", "context",
+	// "global" or "argument" when given on the command line
+	Source string `json:"source" yaml:"source"`
+
+	Installed bool `json:"installed" yaml:"installed"`
+
+	// Path is the binary of the version, empty when not installed
+	Path string `json:"path" yaml:"path"`
+}
+
+// pathResult is the result of 'kuve path'
+type pathResult struct {
+	Tool string `json:"tool" yaml:"tool"`
+
+	// Path is the bin directory to add to PATH
+	Path string `json:"path" yaml:"path"`
+
+	// Installed reports whether a version of the tool is active in the directory
+	Installed bool `json:"installed" yaml:"installed"`
+}
+
+// projectEntry is a version referenced by a project file of the tree, in
+// the results of 'kuve sync' and 'kuve scan'
+type projectEntry struct {
+	// Dir is the directory of the project file, relative to the root
+	Dir    string `json:"dir" yaml:"dir"`
+	File   string `json:"file" yaml:"file"`
+	Format string `json:"format" yaml:"format"`
+
+	Spec       string `json:"spec,omitempty" yaml:"spec,omitempty"`
+	Constraint string `json:"constraint,omitempty" yaml:"constraint,omitempty"`
+
+	// Context is the kubeconfig context of a .kuve.yaml override
+	Context string `json:"context,omitempty" yaml:"context,omitempty"`
+
+	// Version is the resolved version, empty when it could not be resolved
+	Version   string `json:"version" yaml:"version"`
+	Installed bool   `json:"installed" yaml:"installed"`
+
+	// EndOfLife reports whether the minor release is no longer maintained (scan)
+	EndOfLife bool `json:"endOfLife" yaml:"endOfLife"`
+
+	// Issues are the problems found by scan (end of life, cluster skew)
+	Issues []string `json:"issues" yaml:"issues"`
+
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// syncResult is the result of 'kuve sync'
+type syncResult struct {
+	Tool     string          `json:"tool" yaml:"tool"`
+	Root     string          `json:"root" yaml:"root"`
+	Projects []projectEntry  `json:"projects" yaml:"projects"`
+	Versions []installStatus `json:"versions" yaml:"versions"`
+}
+
+// scanCluster is a cluster the versions are checked against by 'kuve scan'
+type scanCluster struct {
+	// Name is the kubeconfig context, empty for --cluster-version
+	Name          string `json:"name,omitempty" yaml:"name,omitempty"`
+	ServerVersion string `json:"serverVersion,omitempty" yaml:"serverVersion,omitempty"`
+	Error         string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// scanResult is the result of 'kuve scan'
+type scanResult struct {
+	Tool string `json:"tool" yaml:"tool"`
+	Root string `json:"root" yaml:"root"`
+
+	// Latest is the latest stable version the end of life is computed from
+	Latest   string         `json:"latest,omitempty" yaml:"latest,omitempty"`
+	Clusters []scanCluster  `json:"clusters" yaml:"clusters"`
+	Projects []projectEntry `json:"projects" yaml:"projects"`
+}
+
+// pinResult is the result of 'kuve pin' and 'kuve unpin'
+type pinResult struct {
+	Tool    string `json:"tool" yaml:"tool"`
+	Version string `json:"version" yaml:"version"`
+	Pinned  bool   `json:"pinned" yaml:"pinned"`
+}
+
+// uninstallResult is the result of 'kuve uninstall'
+type uninstallResult struct {
+	Tool    string `json:"tool" yaml:"tool"`
+	Version string `json:"version" yaml:"version"`
+}
+
+// importResult is the result of 'kuve import'
+type importResult struct {
+	Tool    string `json:"tool" yaml:"tool"`
+	Version string `json:"version" yaml:"version"`
+
+	// Source is the imported file
+	Source string `json:"source" yaml:"source"`
+
+	// Path is the installed binary
+	Path string `json:"path" yaml:"path"`
+}
+
+// bundleEntry is a binary of a bundle
+type bundleEntry struct {
+	Version  string `json:"version" yaml:"version"`
+	Platform string `json:"platform" yaml:"platform"`
+	SHA256   string `json:"sha256" yaml:"sha256"`
+}
+
+// bundleCreateResult is the result of 'kuve bundle create'
+type bundleCreateResult struct {
+	File    string        `json:"file" yaml:"file"`
+	Entries []bundleEntry `json:"entries" yaml:"entries"`
+}
+
+// bundleInstallResult is the result of 'kuve bundle install'
+type bundleInstallResult struct {
+	File     string          `json:"file" yaml:"file"`
+	Platform string          `json:"platform" yaml:"platform"`
+	Versions []installStatus `json:"versions" yaml:"versions"`
+}
+
+// lockResult is the result of 'kuve lock'
+type lockResult struct {
+	Tool string `json:"tool" yaml:"tool"`
+
+	// Spec is the content of the version file
+	Spec    string `json:"spec" yaml:"spec"`
+	Version string `json:"version" yaml:"version"`
+
+	// File is the written lock file
+	File string `json:"file" yaml:"file"`
+
+	// Checksums are the locked checksums keyed by os/arch
+	Checksums map[string]string `json:"checksums" yaml:"checksums"`
+}
+
+// verifyStatus is the outcome of the verification of one version
+type verifyStatus struct {
+	Version  string   `json:"version" yaml:"version"`
+	OK       bool     `json:"ok" yaml:"ok"`
+	Problems []string `json:"problems" yaml:"problems"`
+
+	// Quarantine is where the version was moved with --quarantine
+	Quarantine string `json:"quarantine,omitempty" yaml:"quarantine,omitempty"`
+
+	// Error reports why a failing version could not be quarantined
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// verifyResult is the result of 'kuve verify'
+type verifyResult struct {
+	Tool     string         `json:"tool" yaml:"tool"`
+	Versions []verifyStatus `json:"versions" yaml:"versions"`
+}
+
+// initResult is the result of 'kuve init'
+type initResult struct {
+	Tool    string `json:"tool" yaml:"tool"`
+	Version string `json:"version" yaml:"version"`
+
+	// File is the written version file
+	File string `json:"file" yaml:"file"`
+}
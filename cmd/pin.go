@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"github.com/germainlefebvre4/kuve/internal/kubectl"
+	"github.com/spf13/cobra"
+)
+
+var pinCmd = &cobra.Command{
+	Use:   "pin <version>",
+	Short: "Pin a kubectl version to protect it from removal",
+	Long: `Pin an installed kubectl version. Pinned versions cannot be uninstalled
+unless --force is given.
+
+Example:
+  kuve pin v1.28.0
+  kuve pin 1.28.0`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		version := normalizeVersion(args[0])
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
+		installer := kubectl.NewInstaller(cfg)
+		installer.Out = messages()
+		if err := installer.Pin(version); err != nil {
+			return err
+		}
+
+		return printResult(pinResult{Tool: cfg.Tool().Name, Version: version, Pinned: true})
+	},
+}
+
+var unpinCmd = &cobra.Command{
+	Use:   "unpin <version>",
+	Short: "Unpin a kubectl version",
+	Long: `Remove the removal protection from a pinned kubectl version.
+
+Example:
+  kuve unpin v1.28.0
+  kuve unpin 1.28.0`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		version := normalizeVersion(args[0])
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
+		installer := kubectl.NewInstaller(cfg)
+		installer.Out = messages()
+		if err := installer.Unpin(version); err != nil {
+			return err
+		}
+
+		return printResult(pinResult{Tool: cfg.Tool().Name, Version: version, Pinned: false})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pinCmd)
+	rootCmd.AddCommand(unpinCmd)
+}
@@ -1,9 +1,15 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
+	"github.com/germainlefebvre4/kuve/pkg/config"
 	"github.com/spf13/cobra"
 )
 
@@ -13,6 +19,11 @@ var (
 	buildCommit = "unknown"
 )
 
+var (
+	quiet    bool
+	toolName string
+)
+
 var rootCmd = &cobra.Command{
 	Use:   "kuve",
 	Short: "Kubernetes Client Switcher",
@@ -21,14 +32,26 @@ var rootCmd = &cobra.Command{
 It helps you manage multiple kubectl versions on your system,
 allowing you to install, switch, and use different versions
 based on your needs or project requirements.`,
-	Version: appVersion,
+	Version:           appVersion,
+	PersistentPreRunE: validateOutput,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
+// SIGINT and SIGTERM cancel the command context so that in-flight downloads
+// and cluster calls stop and partial installs are cleaned up.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := rootCmd.ExecuteContext(ctx)
+	interrupted := errors.Is(ctx.Err(), context.Canceled)
+	stop()
+
+	if interrupted {
+		fmt.Fprintln(os.Stderr, "Interrupted")
+		os.Exit(exitInterrupted)
+	}
+	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
 
@@ -39,4 +62,32 @@ func init() {
 
 	// Global flags can be added here
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "suppress download progress output")
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "output", outputText, "output format of the results: text, json or yaml")
+	rootCmd.PersistentFlags().StringVar(&toolName, "tool", config.DefaultToolName, "tool to manage (kubectl or a tool defined in the configuration file)")
+}
+
+// loadConfig loads the configuration managing the tool selected with --tool
+func loadConfig() (*config.Config, error) {
+	cfg, err := config.New()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config: %w", err)
+	}
+	return cfg.ForTool(toolName)
+}
+
+// normalizeVersion adds the 'v' prefix the installed versions are named with
+func normalizeVersion(v string) string {
+	if v != "" && !strings.HasPrefix(v, "v") {
+		return "v" + v
+	}
+	return v
+}
+
+// requireKubectl rejects commands that only support kubectl when another tool is selected
+func requireKubectl(command string) error {
+	if toolName != "" && toolName != config.DefaultToolName {
+		return fmt.Errorf("'kuve %s' only supports kubectl", command)
+	}
+	return nil
 }
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/germainlefebvre4/kuve/internal/version"
+	"github.com/spf13/cobra"
+)
+
+var (
+	scanContexts       []string
+	scanClusterVersion []string
+)
+
+var scanCmd = &cobra.Command{
+	Use:   "scan [root]",
+	Short: "Report the kubectl versions pinned in a project tree",
+	Long: `Walk a project tree (the current directory by default), like 'kuve sync',
+and report which directories pin which kubectl versions, without installing
+anything.
+
+Versions whose minor release is no longer maintained (older than the last
+three minor releases) are flagged as end of life. With --context, the
+version of the cluster of each kubeconfig context is detected, and with
+--cluster-version, cluster versions are given directly; versions more than
+one minor version away from one of these clusters are flagged as outside
+the supported skew.
+
+Example:
+  kuve scan
+  kuve scan ~/src/monorepo --context prod --context staging
+  kuve scan --cluster-version 1.29 --output json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		tool := cfg.Tool()
+		if (len(scanContexts) > 0 || len(scanClusterVersion) > 0) && !tool.IsKubectl() {
+			return fmt.Errorf("--context and --cluster-version are only supported for kubectl")
+		}
+
+		root, projects, err := scanProjects(cfg, args)
+		if err != nil {
+			return err
+		}
+
+		manager := version.NewManager(cfg)
+		out := messages()
+		result := scanResult{Tool: tool.Name, Root: root, Clusters: []scanCluster{}}
+
+		for _, name := range scanContexts {
+			cluster := scanCluster{Name: name}
+			if serverVersion, _, err := manager.DetectContextVersion(cmd.Context(), name); err != nil {
+				fmt.Fprintf(out, "Warning: skipping context %s: %v\n", name, err)
+				cluster.Error = err.Error()
+			} else {
+				cluster.ServerVersion = serverVersion
+			}
+			result.Clusters = append(result.Clusters, cluster)
+		}
+		for _, v := range scanClusterVersion {
+			if !strings.HasPrefix(v, "v") {
+				v = "v" + v
+			}
+			result.Clusters = append(result.Clusters, scanCluster{ServerVersion: v})
+		}
+
+		// End of life is only known for Kubernetes releases
+		if tool.IsKubectl() {
+			if result.Latest, err = manager.GetStableVersion(cmd.Context()); err != nil {
+				fmt.Fprintf(out, "Warning: end of life not checked, the latest release is unknown: %v\n", err)
+			}
+		}
+
+		result.Projects = resolveProjects(cmd.Context(), manager, projects)
+		for idx := range result.Projects {
+			checkProject(&result.Projects[idx], result.Latest, result.Clusters)
+		}
+		if result.Projects == nil {
+			result.Projects = []projectEntry{}
+		}
+
+		if structuredOutput() {
+			return printResult(result)
+		}
+
+		printScan(out, result)
+		return nil
+	},
+}
+
+// checkProject flags the end of life and the clusters out of skew of a resolved version
+func checkProject(entry *projectEntry, latest string, clusters []scanCluster) {
+	if entry.Version == "" {
+		return
+	}
+
+	if latest != "" {
+		if eol, err := version.IsEndOfLife(entry.Version, latest); err == nil && eol {
+			entry.EndOfLife = true
+			entry.Issues = append(entry.Issues, fmt.Sprintf("end of life (latest is %s)", latest))
+		}
+	}
+
+	for _, cluster := range clusters {
+		if cluster.ServerVersion == "" {
+			continue
+		}
+		skew, err := version.MinorSkew(entry.Version, cluster.ServerVersion)
+		if err != nil || skew > version.MaxKubectlSkew || skew < -version.MaxKubectlSkew {
+			name := cluster.ServerVersion
+			if cluster.Name != "" {
+				name = fmt.Sprintf("%s (%s)", cluster.Name, cluster.ServerVersion)
+			}
+			entry.Issues = append(entry.Issues, fmt.Sprintf("outside the supported skew of %s", name))
+		}
+	}
+}
+
+// printScan prints the scan result in text format
+func printScan(out io.Writer, result scanResult) {
+	if len(result.Projects) == 0 {
+		fmt.Fprintf(out, "No %s version referenced in %s\n", result.Tool, result.Root)
+		return
+	}
+
+	flagged := 0
+	fmt.Fprintf(out, "%s versions referenced in %s:\n", result.Tool, result.Root)
+	for _, entry := range result.Projects {
+		source := entry.File
+		if entry.Context != "" {
+			source += ", context " + entry.Context
+		}
+		if entry.Constraint != "" && entry.Spec == "" {
+			source += ", constraint " + entry.Constraint
+		}
+
+		line := fmt.Sprintf("  %-30s %-10s (%s)", entry.Dir, entry.Version, source)
+		switch {
+		case entry.Error != "":
+			line = fmt.Sprintf("  %-30s %-10s (%s) error: %s", entry.Dir, referenceSpec(entry), source, entry.Error)
+			flagged++
+		case len(entry.Issues) > 0:
+			line += " ! " + strings.Join(entry.Issues, ", ")
+			flagged++
+		}
+		if entry.Error == "" && !entry.Installed {
+			line += " [not installed]"
+		}
+		fmt.Fprintln(out, line)
+	}
+
+	if flagged > 0 {
+		fmt.Fprintf(out, "\n%d reference(s) flagged\n", flagged)
+	}
+}
+
+func init() {
+	scanCmd.Flags().StringArrayVar(&scanContexts, "context", nil, "kubeconfig context whose cluster version is checked for skew (repeatable)")
+	scanCmd.Flags().StringArrayVar(&scanClusterVersion, "cluster-version", nil, "cluster version checked for skew (repeatable)")
+	rootCmd.AddCommand(scanCmd)
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/germainlefebvre4/kuve/internal/kubectl"
+	"github.com/germainlefebvre4/kuve/internal/version"
+	"github.com/germainlefebvre4/kuve/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// sourceNone is the status source when no version applies
+const sourceNone = "none"
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the effective kubectl version and where it comes from",
+	Long: `Show the kubectl version that applies to the current directory and why:
+the KUVE_KUBECTL_VERSION environment variable, the project file with its path
+(.kubernetes-version, .kuve.yaml, .tool-versions or mise.toml), the context
+override of .kuve.yaml or the global version, the same selection as 'kuve use'.
+
+The status tells whether the version is installed, the path of its binary
+and whether the active symlink points to it. When the current cluster is
+reachable, the kubectl version is compared with the server version, and a
+skew beyond one minor version, unsupported by Kubernetes, is reported.
+
+With --tool, the status of that tool is shown, without the cluster skew.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		tool := cfg.Tool()
+
+		manager := version.NewManager(cfg)
+		installer := kubectl.NewInstaller(cfg)
+		result := statusResult{Tool: tool.Name, Source: sourceNone, Path: cfg.CurrentSymlink}
+
+		selection, resolved, checksum, err := effectiveVersion(cmd.Context(), cfg, manager, installer.Platform.String())
+		if err != nil && !errors.Is(err, version.ErrNoVersionFile) {
+			return err
+		}
+		if selection != nil {
+			result.Source = selection.Source
+			result.Origin = selection.Origin
+			result.Version = resolved
+			result.Constraint = selection.Constraint
+			result.Context = selection.Context
+			result.Locked = checksum != ""
+
+			result.Installed = manager.IsVersionInstalled(result.Version)
+			if result.Installed {
+				result.Binary = manager.BinaryPath(result.Version)
+			}
+		}
+
+		result.Active, _ = manager.GetCurrentVersion()
+		if result.Binary != "" {
+			target, err := os.Readlink(cfg.CurrentSymlink)
+			result.Matches = err == nil && target == result.Binary
+		}
+
+		if tool.IsKubectl() {
+			result.Cluster = clusterSkew(cmd, manager, result)
+		}
+
+		if structuredOutput() {
+			return printResult(result)
+		}
+
+		printStatus(result, selection, tool.VersionFile)
+		return nil
+	},
+}
+
+// effectiveVersion selects the version that applies to the current directory,
+// like 'kuve use' without --from-cluster, and returns it resolved. Versions
+// of a version file are resolved with the .kuve.lock next to it, if any, and
+// then come with the locked checksum of the platform.
+func effectiveVersion(ctx context.Context, cfg *config.Config, manager *version.Manager, platform string) (selection *version.Selection, resolved, checksum string, err error) {
+	selection, err = version.SelectVersion(cfg)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	if selection.Source == version.SourceFile && selection.Format == version.FormatVersionFile {
+		if resolved, checksum, err = lockedVersion(cfg, selection, platform); err != nil {
+			return nil, "", "", err
+		}
+		if checksum != "" {
+			return selection, resolved, checksum, nil
+		}
+	}
+
+	if resolved, err = manager.ResolveSelection(ctx, selection); err != nil {
+		return nil, "", "", err
+	}
+	return selection, resolved, "", nil
+}
+
+// clusterSkew compares the effective kubectl version, or the active one when
+// no version applies, with the version of the current cluster
+func clusterSkew(cmd *cobra.Command, manager *version.Manager, result statusResult) *clusterStatus {
+	client := result.Version
+	if client == "" {
+		client = result.Active
+	}
+	if client == "" {
+		return nil
+	}
+
+	serverVersion, _, err := manager.DetectClusterVersionWithRaw(cmd.Context())
+	if err != nil {
+		return &clusterStatus{Error: err.Error()}
+	}
+
+	status := &clusterStatus{Reachable: true, ServerVersion: serverVersion}
+	skew, err := version.MinorSkew(client, serverVersion)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	status.Skew = skew
+	status.Supported = skew >= -version.MaxKubectlSkew && skew <= version.MaxKubectlSkew
+	return status
+}
+
+// printStatus prints the status in text format
+func printStatus(result statusResult, selection *version.Selection, versionFile string) {
+	if selection == nil {
+		fmt.Printf("No %s version applies: no %s file in current or parent directories and no global version\n", result.Tool, versionFile)
+	} else {
+		fmt.Printf("%s %s\n", result.Tool, result.Version)
+		source := selection.Describe()
+		if result.Locked {
+			source += fmt.Sprintf(" (%s locked by .kuve.lock)", selection.Spec)
+		}
+		fmt.Printf("  Source:    %s\n", source)
+		if result.Installed {
+			fmt.Printf("  Installed: yes, %s\n", result.Binary)
+		} else {
+			fmt.Printf("  Installed: no, run 'kuve install %s'\n", result.Version)
+		}
+	}
+
+	switch {
+	case result.Active == "":
+		fmt.Printf("  Active:    none (%s)\n", result.Path)
+	case result.Matches:
+		fmt.Printf("  Active:    %s (%s)\n", result.Active, result.Path)
+	case selection != nil:
+		fmt.Printf("  Active:    %s (%s), differs from %s, run 'kuve use' to switch\n", result.Active, result.Path, result.Version)
+	default:
+		fmt.Printf("  Active:    %s (%s)\n", result.Active, result.Path)
+	}
+
+	cluster := result.Cluster
+	switch {
+	case cluster == nil:
+	case !cluster.Reachable:
+		fmt.Printf("  Cluster:   unreachable (%s)\n", cluster.Error)
+	case cluster.Error != "":
+		fmt.Printf("  Cluster:   %s (%s)\n", cluster.ServerVersion, cluster.Error)
+	case cluster.Supported:
+		fmt.Printf("  Cluster:   %s, skew %+d minor version(s), supported\n", cluster.ServerVersion, cluster.Skew)
+	default:
+		fmt.Printf("  Cluster:   %s, skew %+d minor version(s), unsupported: kubectl must be within %d minor version of the server\n",
+			cluster.ServerVersion, cluster.Skew, version.MaxKubectlSkew)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+}
@@ -5,7 +5,6 @@ import (
 
 	"github.com/germainlefebvre4/kuve/internal/kubectl"
 	"github.com/germainlefebvre4/kuve/internal/version"
-	"github.com/germainlefebvre4/kuve/pkg/config"
 	"github.com/spf13/cobra"
 )
 
@@ -14,24 +13,51 @@ var switchCmd = &cobra.Command{
 	Short: "Switch to a specific kubectl version",
 	Long: `Switch to a specific kubectl version. The version must be installed first.
 
+With "-" as version, switches back to the version that was active before
+the last switch, like 'cd -' (see 'kuve history').
+
 Example:
   kuve switch v1.28.0
-  kuve switch 1.28.0`,
+  kuve switch 1.28.0
+  kuve switch -`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		version := args[0]
+		requestedVersion := args[0]
 
-		cfg, err := config.New()
+		cfg, err := loadConfig()
 		if err != nil {
-			return fmt.Errorf("failed to create config: %w", err)
+			return err
+		}
+
+		manager := version.NewManager(cfg)
+
+		if requestedVersion == "-" {
+			history, err := version.ReadHistory(cfg)
+			if err != nil {
+				return err
+			}
+			currentVersion, _ := manager.GetCurrentVersion()
+			if requestedVersion, err = version.PreviousVersion(history, cfg.Tool().Name, currentVersion); err != nil {
+				return err
+			}
+		}
+
+		// Normalize version
+		if len(requestedVersion) > 0 && requestedVersion[0] != 'v' {
+			requestedVersion = "v" + requestedVersion
 		}
 
 		installer := kubectl.NewInstaller(cfg)
-		if err := installer.Switch(version); err != nil {
+		installer.Out = messages()
+		if err := switchVersion(cfg, manager, installer, requestedVersion, version.TriggerManual); err != nil {
 			return err
 		}
 
-		return nil
+		return printResult(switchResult{
+			Tool:    cfg.Tool().Name,
+			Version: requestedVersion,
+			Path:    cfg.CurrentSymlink,
+		})
 	},
 }
 
@@ -40,9 +66,9 @@ var currentCmd = &cobra.Command{
 	Short: "Show the current kubectl version",
 	Long:  `Display the currently active kubectl version managed by kuve.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, err := config.New()
+		cfg, err := loadConfig()
 		if err != nil {
-			return fmt.Errorf("failed to create config: %w", err)
+			return err
 		}
 
 		manager := version.NewManager(cfg)
@@ -51,7 +77,11 @@ var currentCmd = &cobra.Command{
 			return err
 		}
 
-		fmt.Printf("Current kubectl version: %s\n", currentVersion)
+		if structuredOutput() {
+			return printResult(currentResult{Tool: cfg.Tool().Name, Version: currentVersion})
+		}
+
+		fmt.Printf("Current %s version: %s\n", cfg.Tool().Name, currentVersion)
 		return nil
 	},
 }
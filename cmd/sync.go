@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/germainlefebvre4/kuve/internal/kubectl"
+	"github.com/germainlefebvre4/kuve/internal/version"
+	"github.com/germainlefebvre4/kuve/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncConcurrency int
+	syncDryRun      bool
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync [root]",
+	Short: "Install every kubectl version referenced in a project tree",
+	Long: `Walk a project tree (the current directory by default) and install every
+kubectl version referenced by its project files: .kubernetes-version,
+.kuve.yaml (including the per-context versions), .tool-versions, mise.toml
+and the formats enabled in the versionFiles setting.
+
+Paths ignored by the .gitignore files of the tree are skipped. Minor
+versions and constraints are resolved like 'kuve install' and 'kuve use',
+and the missing versions are downloaded concurrently. Versions already
+installed are left untouched, so the command can be rerun safely.
+
+With --tool, the versions of that tool are synchronized instead.
+
+Example:
+  kuve sync
+  kuve sync ~/src/monorepo --concurrency 8
+  kuve sync --dry-run`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
+		// Ensure directories exist
+		if err := cfg.EnsureDirectories(); err != nil {
+			return fmt.Errorf("failed to create directories: %w", err)
+		}
+
+		root, projects, err := scanProjects(cfg, args)
+		if err != nil {
+			return err
+		}
+
+		manager := version.NewManager(cfg)
+		installer := kubectl.NewInstaller(cfg)
+		installer.Quiet = quiet
+		installer.Out = messages()
+
+		out := messages()
+		result := syncResult{Tool: cfg.Tool().Name, Root: root, Projects: []projectEntry{}, Versions: []installStatus{}}
+
+		// Resolve the references, each distinct version is installed once
+		var missing []string
+		seen := map[string]bool{}
+		failed := 0
+		for _, entry := range resolveProjects(cmd.Context(), manager, projects) {
+			result.Projects = append(result.Projects, entry)
+			switch {
+			case entry.Error != "":
+				fmt.Fprintf(out, "%s: %s\n", entry.Dir, entry.Error)
+				result.Versions = append(result.Versions, installStatus{Version: referenceSpec(entry), Status: statusFailed, Error: entry.Error})
+				failed++
+			case seen[entry.Version]:
+			case entry.Installed:
+				seen[entry.Version] = true
+				result.Versions = append(result.Versions, installStatus{Version: entry.Version, Status: statusAlreadyInstalled})
+			default:
+				seen[entry.Version] = true
+				missing = append(missing, entry.Version)
+			}
+		}
+
+		fmt.Fprintf(out, "Found %d %s reference(s) in %s, %d version(s) to install\n",
+			len(result.Projects), cfg.Tool().Name, root, len(missing))
+
+		if syncDryRun {
+			for _, v := range missing {
+				fmt.Fprintf(out, "  would install %s\n", v)
+			}
+			return printResult(result)
+		}
+
+		installed := 0
+		for _, r := range installer.InstallMany(cmd.Context(), missing, syncConcurrency) {
+			if r.Err != nil {
+				fmt.Fprintf(out, "  %s: %v\n", r.Version, r.Err)
+				result.Versions = append(result.Versions, installStatus{Version: r.Version, Status: statusFailed, Error: r.Err.Error()})
+				failed++
+				continue
+			}
+			installed++
+			result.Versions = append(result.Versions, newInstallStatus(r))
+		}
+		fmt.Fprintf(out, "Installed %d version(s), %d failed\n", installed, failed)
+
+		if err := printResult(result); err != nil {
+			return err
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d version(s) failed to sync", failed)
+		}
+		return nil
+	},
+}
+
+// scanProjects returns the absolute root of the tree, the current directory
+// without argument, and the project files of the tree declaring the tool
+func scanProjects(cfg *config.Config, args []string) (string, []*version.ProjectVersion, error) {
+	root := "."
+	if len(args) > 0 {
+		root = args[0]
+	}
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return "", nil, err
+	}
+
+	projectFiles, err := version.NewProjectFiles(cfg.Settings)
+	if err != nil {
+		return "", nil, err
+	}
+	projects, err := version.ScanTree(root, projectFiles, cfg.Tool())
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to scan %s: %w", root, err)
+	}
+
+	// Show the references relative to the root
+	for _, project := range projects {
+		if rel, err := filepath.Rel(root, project.Path); err == nil {
+			project.Path = rel
+		}
+	}
+	return root, projects, nil
+}
+
+// resolveProjects resolves the versions referenced by the project files, the
+// version or constraint of each file followed by its per-context versions
+func resolveProjects(ctx context.Context, manager *version.Manager, projects []*version.ProjectVersion) []projectEntry {
+	type resolution struct {
+		version string
+		err     error
+	}
+	cache := map[string]resolution{}
+
+	var entries []projectEntry
+	for _, project := range projects {
+		base := projectEntry{
+			Dir:    filepath.ToSlash(project.Dir()),
+			File:   filepath.Base(project.Path),
+			Format: project.Format,
+			Issues: []string{},
+		}
+
+		references := []projectEntry{base}
+		references[0].Spec = project.Spec
+		references[0].Constraint = project.Constraint
+
+		contexts := make([]string, 0, len(project.Contexts))
+		for name := range project.Contexts {
+			contexts = append(contexts, name)
+		}
+		sort.Strings(contexts)
+		for _, name := range contexts {
+			entry := base
+			entry.Spec = project.Contexts[name]
+			entry.Context = name
+			references = append(references, entry)
+		}
+
+		for _, entry := range references {
+			key := entry.File + "|" + entry.Spec + "|" + entry.Constraint
+			r, ok := cache[key]
+			if !ok {
+				// Like 'kuve use', which also rejects anything but full versions
+				// since project files are untrusted
+				selection := &version.Selection{Spec: entry.Spec, Constraint: entry.Constraint, Origin: entry.File}
+				r.version, r.err = manager.ResolveSelection(ctx, selection)
+				cache[key] = r
+			}
+			if r.err != nil {
+				entry.Error = r.err.Error()
+			} else {
+				entry.Version = r.version
+				entry.Installed = manager.IsVersionInstalled(r.version)
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// referenceSpec returns the version or constraint of a reference as written
+func referenceSpec(entry projectEntry) string {
+	if entry.Spec != "" {
+		return entry.Spec
+	}
+	return entry.Constraint
+}
+
+func init() {
+	syncCmd.Flags().IntVarP(&syncConcurrency, "concurrency", "j", kubectl.DefaultConcurrency, "maximum number of parallel downloads")
+	syncCmd.Flags().BoolVar(&syncDryRun, "dry-run", false, "list the versions to install without installing them")
+	rootCmd.AddCommand(syncCmd)
+}
@@ -1,39 +1,45 @@
 package cmd
 
 import (
-	"fmt"
-
 	"github.com/germainlefebvre4/kuve/internal/kubectl"
-	"github.com/germainlefebvre4/kuve/pkg/config"
 	"github.com/spf13/cobra"
 )
 
+var (
+	forceUninstall bool
+)
+
 var uninstallCmd = &cobra.Command{
 	Use:   "uninstall <version>",
 	Short: "Uninstall a specific kubectl version",
 	Long: `Remove a specific kubectl version from your system.
 
+Pinned versions are protected and require --force to be removed.
+
 Example:
   kuve uninstall v1.28.0
-  kuve uninstall 1.28.0`,
+  kuve uninstall 1.28.0
+  kuve uninstall --force v1.27.0`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		version := args[0]
+		version := normalizeVersion(args[0])
 
-		cfg, err := config.New()
+		cfg, err := loadConfig()
 		if err != nil {
-			return fmt.Errorf("failed to create config: %w", err)
+			return err
 		}
 
 		installer := kubectl.NewInstaller(cfg)
-		if err := installer.Uninstall(version); err != nil {
+		installer.Out = messages()
+		if err := installer.Uninstall(version, forceUninstall); err != nil {
 			return err
 		}
 
-		return nil
+		return printResult(uninstallResult{Tool: cfg.Tool().Name, Version: version})
 	},
 }
 
 func init() {
+	uninstallCmd.Flags().BoolVarP(&forceUninstall, "force", "f", false, "uninstall even if the version is pinned")
 	rootCmd.AddCommand(uninstallCmd)
 }
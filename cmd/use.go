@@ -3,6 +3,9 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/germainlefebvre4/kuve/internal/kubectl"
 	"github.com/germainlefebvre4/kuve/internal/version"
@@ -12,6 +15,8 @@ import (
 
 var (
 	fromCluster bool
+	ifMissing   bool
+	useHook     bool
 )
 
 var useCmd = &cobra.Command{
@@ -20,16 +25,32 @@ var useCmd = &cobra.Command{
 	Long: `Switch to the kubectl version specified in the .kubernetes-version file or detect from cluster.
 
 This command searches for a .kubernetes-version file in the current directory
-and parent directories. If found, it switches to that version.
+and parent directories. If found, it switches to that version. The
+KUVE_KUBECTL_VERSION environment variable takes precedence over version
+files, and the global version (see 'kuve global') applies when no version
+file is found.
+
+The .kuve.yaml, asdf .tool-versions and mise.toml project files are read
+too, in the order set by the versionFiles setting. A .kuve.yaml file may
+declare a version constraint instead of a version, companion binaries to
+install, and versions used with specific kubeconfig contexts.
 
 With --from-cluster flag, it detects the Kubernetes version from the current
 cluster context and switches to the matching kubectl version.
 
-If the version is not installed, it will be installed automatically.`,
+If the version is not installed, it is installed first (--if-missing, on by
+default); the output says whether the version was installed or already
+present. With --if-missing=false, a missing version is an error instead.
+
+When a .kuve.lock file (see 'kuve lock') sits next to the version file, the
+locked version is used and the binary is verified against the locked checksum
+of this platform. A mismatching binary is refused.
+
+With --tool, the version file of that tool is used instead (e.g. .helm-version).`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, err := config.New()
+		cfg, err := loadConfig()
 		if err != nil {
-			return fmt.Errorf("failed to create config: %w", err)
+			return err
 		}
 
 		// Ensure directories exist
@@ -39,33 +60,81 @@ If the version is not installed, it will be installed automatically.`,
 
 		manager := version.NewManager(cfg)
 		installer := kubectl.NewInstaller(cfg)
+		installer.Quiet = quiet
+		installer.Out = messages()
+		if verifySignature {
+			if err := requireKubectl("use --verify-signature"); err != nil {
+				return err
+			}
+			installer.VerifySignature = true
+		}
+
+		out := messages()
+		result := useResult{Tool: cfg.Tool().Name, Path: cfg.CurrentSymlink}
 
 		var requestedVersion string
 
+		// lockedChecksum is the checksum pinned by a .kuve.lock next to the version file
+		var lockedChecksum string
+
 		if fromCluster {
+			if err := requireKubectl("use --from-cluster"); err != nil {
+				return err
+			}
+
 			// Detect version from cluster
-			fmt.Println("Detecting Kubernetes version from current cluster context...")
-			rawVersion, normalizedVersion, err := manager.DetectClusterVersionWithRaw()
+			fmt.Fprintln(out, "Detecting Kubernetes version from current cluster context...")
+			rawVersion, normalizedVersion, err := manager.DetectClusterVersionWithRaw(cmd.Context())
 			if err != nil {
 				return fmt.Errorf("failed to detect cluster version: %w", err)
 			}
 			if rawVersion != normalizedVersion {
-				fmt.Printf("Detected cluster version: %s (using kubectl %s)\n", rawVersion, normalizedVersion)
+				fmt.Fprintf(out, "Detected cluster version: %s (using kubectl %s)\n", rawVersion, normalizedVersion)
 			} else {
-				fmt.Printf("Detected cluster version: %s\n", rawVersion)
+				fmt.Fprintf(out, "Detected cluster version: %s\n", rawVersion)
 			}
 			requestedVersion = normalizedVersion
+			result.Source = "cluster"
+			result.Cluster = &clusterResult{ServerVersion: rawVersion, Version: normalizedVersion}
 		} else {
-			// Find .kubernetes-version file
-			requestedVersion, err = version.FindVersionFile()
+			// Environment override, project file (.kubernetes-version for
+			// kubectl, .kuve.yaml, .tool-versions, mise.toml) or global version
+			selection, resolved, checksum, err := effectiveVersion(cmd.Context(), cfg, manager, installer.Platform.String())
 			if err != nil {
-				return fmt.Errorf("error searching for version file: %w", err)
+				return err
+			}
+
+			spec := selection.Spec
+			requestedVersion = resolved
+			lockedChecksum = checksum
+			result.Source = selection.Source
+			switch selection.Source {
+			case version.SourceEnv:
+				fmt.Fprintf(out, "Using version %s from %s\n", spec, selection.Describe())
+				result.Origin = selection.Origin
+			case version.SourceGlobal:
+				fmt.Fprintf(out, "No %s file found, using global version %s\n", cfg.Tool().VersionFile, spec)
+				result.Origin = selection.Origin
+			case version.SourceContext:
+				fmt.Fprintf(out, "Using version %s for context %s from %s\n", spec, selection.Context, filepath.Base(selection.Origin))
+				result.File = selection.Origin
+				result.Context = selection.Context
+			default:
+				if spec == "" {
+					fmt.Fprintf(out, "Resolved constraint %s of %s to %s\n", selection.Constraint, filepath.Base(selection.Origin), resolved)
+				} else {
+					fmt.Fprintf(out, "Found version %s in %s file\n", spec, filepath.Base(selection.Origin))
+				}
+				result.File = selection.Origin
 			}
 
-			if requestedVersion == "" {
-				return fmt.Errorf("no .kubernetes-version file found in current or parent directories")
+			// Companion binaries required by the project file (.kuve.yaml)
+			if len(selection.Companions) > 0 {
+				if !cfg.Tool().IsKubectl() {
+					return fmt.Errorf("companion binaries are only supported for kubectl (%s)", selection.Origin)
+				}
+				installer.With = selection.Companions
 			}
-			fmt.Printf("Found version %s in .kubernetes-version file\n", requestedVersion)
 		}
 
 		// Normalize version
@@ -73,42 +142,96 @@ If the version is not installed, it will be installed automatically.`,
 			requestedVersion = "v" + requestedVersion
 		}
 
-		// Check if version is installed
-		if !manager.IsVersionInstalled(requestedVersion) {
-			fmt.Printf("Version %s is not installed. Installing...\n", requestedVersion)
-			if err := installer.Install(requestedVersion); err != nil {
+		// Check if version is installed, with the companions required by the project
+		installed := manager.IsVersionInstalled(requestedVersion)
+		missingCompanions := missingCompanions(manager, requestedVersion, installer.With)
+		switch {
+		case installed && len(missingCompanions) == 0:
+			fmt.Fprintf(out, "Using installed %s %s\n", cfg.Tool().Name, requestedVersion)
+		case !ifMissing && !installed:
+			return fmt.Errorf("%w: %s %s, run 'kuve install %s' or drop --if-missing=false",
+				kubectl.ErrNotInstalled, cfg.Tool().Name, requestedVersion, requestedVersion)
+		case !ifMissing:
+			return fmt.Errorf("%w: %s for %s %s, run 'kuve install %s --with %s' or drop --if-missing=false",
+				kubectl.ErrNotInstalled, strings.Join(missingCompanions, ", "), cfg.Tool().Name, requestedVersion,
+				requestedVersion, strings.Join(missingCompanions, ","))
+		case installed:
+			fmt.Fprintf(out, "Companions %s of %s are not installed. Installing (--if-missing)...\n", strings.Join(missingCompanions, ", "), requestedVersion)
+			if err := installer.Install(cmd.Context(), requestedVersion); err != nil {
+				return fmt.Errorf("failed to install companions: %w", err)
+			}
+		default:
+			fmt.Fprintf(out, "Version %s is not installed. Installing (--if-missing)...\n", requestedVersion)
+			if err := installer.Install(cmd.Context(), requestedVersion); err != nil {
 				return fmt.Errorf("failed to install version: %w", err)
 			}
 		}
 
+		// Refuse binaries that differ from the locked artifact
+		if lockedChecksum != "" {
+			if err := installer.VerifyInstalled(requestedVersion, lockedChecksum); err != nil {
+				if !installed {
+					installer.Uninstall(requestedVersion, true)
+				}
+				return fmt.Errorf("%s %s does not match %s: %w", cfg.Tool().Name, requestedVersion, config.LockFileName, err)
+			}
+			fmt.Fprintf(out, "Verified %s %s against %s\n", cfg.Tool().Name, requestedVersion, config.LockFileName)
+			result.Locked = true
+		}
+
 		// Switch to the version
-		if err := installer.Switch(requestedVersion); err != nil {
+		trigger := version.TriggerUse
+		if fromCluster {
+			trigger = version.TriggerCluster
+		} else if useHook {
+			trigger = version.TriggerHook
+		}
+		if err := switchVersion(cfg, manager, installer, requestedVersion, trigger); err != nil {
 			return fmt.Errorf("failed to switch version: %w", err)
 		}
 
-		return nil
+		result.Version = requestedVersion
+		result.Installed = !installed
+		return printResult(result)
 	},
 }
 
+// missingCompanions returns the companions of a version that are not installed
+func missingCompanions(manager *version.Manager, v string, companions []string) []string {
+	installed := manager.InstalledCompanions(v)
+	var missing []string
+	for _, name := range companions {
+		if !slices.Contains(installed, name) {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
 var initCmd = &cobra.Command{
-	Use:   "init [version]",
-	Short: "Create a .kubernetes-version file",
-	Long: `Create a .kubernetes-version file in the current directory.
+	Use:     "init [version]",
+	Aliases: []string{"local"},
+	Short:   "Create a .kubernetes-version file",
+	Long: `Create a .kubernetes-version file in the current directory, or the version
+file of the tool selected with --tool.
+
+If no version is specified, the current active version will be used.
 
-If no version is specified, the current active version will be used.`,
+'kuve local' is an alias: the version file sets the local version of a
+project, while 'kuve global' sets the default used everywhere else.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
 		var versionToWrite string
 
 		if len(args) > 0 {
 			versionToWrite = args[0]
 		} else {
 			// Use current version
-			cfg, err := config.New()
-			if err != nil {
-				return fmt.Errorf("failed to create config: %w", err)
-			}
-
 			manager := version.NewManager(cfg)
 			currentVersion, err := manager.GetCurrentVersion()
 			if err != nil {
@@ -118,23 +241,27 @@ If no version is specified, the current active version will be used.`,
 		}
 
 		// Normalize version
-		if versionToWrite[0] != 'v' {
-			versionToWrite = "v" + versionToWrite
+		versionToWrite = normalizeVersion(versionToWrite)
+		if versionToWrite == "" {
+			return fmt.Errorf("version cannot be empty")
 		}
 
 		// Write version file
-		versionFile := config.VersionFileName
+		versionFile := cfg.Tool().VersionFile
 		if err := os.WriteFile(versionFile, []byte(versionToWrite+"\n"), 0644); err != nil {
 			return fmt.Errorf("failed to write version file: %w", err)
 		}
 
-		fmt.Printf("Created %s with version %s\n", versionFile, versionToWrite)
-		return nil
+		fmt.Fprintf(messages(), "Created %s with version %s\n", versionFile, versionToWrite)
+		return printResult(initResult{Tool: cfg.Tool().Name, Version: versionToWrite, File: versionFile})
 	},
 }
 
 func init() {
 	useCmd.Flags().BoolVarP(&fromCluster, "from-cluster", "c", false, "detect and use version from current Kubernetes cluster")
+	useCmd.Flags().BoolVar(&useHook, "hook", false, "record the switch as triggered by a shell hook")
+	useCmd.Flags().BoolVar(&ifMissing, "if-missing", true, "install the version when it is not installed (--if-missing=false fails instead)")
+	useCmd.Flags().BoolVar(&verifySignature, "verify-signature", false, "require a valid cosign signature of the installed kubectl artifacts")
 	rootCmd.AddCommand(useCmd)
 	rootCmd.AddCommand(initCmd)
 }
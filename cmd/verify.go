@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/germainlefebvre4/kuve/internal/kubectl"
+	"github.com/germainlefebvre4/kuve/internal/version"
+	"github.com/spf13/cobra"
+)
+
+var (
+	verifyAll        bool
+	verifyUpstream   bool
+	verifyQuarantine bool
+	verifyForce      bool
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify [version...]",
+	Short: "Check the integrity of installed versions",
+	Long: `Check that installed binaries have not been corrupted or tampered with.
+
+Each binary is hashed and compared with the checksum recorded when it was
+installed. Versions installed without recorded checksums, or all versions with
+--upstream, are compared with the published checksums instead. The binary is
+then run to check that it reports the expected version.
+
+With --quarantine, failing versions are moved to ~/.kuve/quarantine/ so that
+they can no longer be used. Pinned versions are reported but left in place
+unless --force is given.
+
+Example:
+  kuve verify v1.28.3
+  kuve verify --all
+  kuve verify --all --upstream --quarantine`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if verifyAll == (len(args) > 0) {
+			return fmt.Errorf("specify versions to verify or --all")
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
+		manager := version.NewManager(cfg)
+		installer := kubectl.NewInstaller(cfg)
+		installer.Quiet = quiet
+		installer.Out = messages()
+
+		out := messages()
+		result := verifyResult{Tool: cfg.Tool().Name, Versions: []verifyStatus{}}
+
+		versions := args
+		if verifyAll {
+			versions, err = manager.ListInstalledVersions()
+			if err != nil {
+				return fmt.Errorf("failed to list installed versions: %w", err)
+			}
+			if len(versions) == 0 {
+				fmt.Fprintf(out, "No %s versions installed.\n", cfg.Tool().Name)
+				return printResult(result)
+			}
+		}
+
+		failed := 0
+		for _, v := range versions {
+			if v == "" {
+				return fmt.Errorf("version cannot be empty")
+			}
+			if !strings.HasPrefix(v, "v") {
+				v = "v" + v
+			}
+
+			verified := installer.Verify(cmd.Context(), v, kubectl.VerifyOptions{Upstream: verifyUpstream})
+			status := verifyStatus{Version: v, OK: verified.OK(), Problems: nonNil(verified.Problems)}
+			if status.OK {
+				fmt.Fprintf(out, "%s %s: OK\n", cfg.Tool().Name, v)
+				result.Versions = append(result.Versions, status)
+				continue
+			}
+
+			failed++
+			fmt.Fprintf(out, "%s %s: FAILED\n", cfg.Tool().Name, v)
+			for _, problem := range verified.Problems {
+				fmt.Fprintf(out, "  - %s\n", problem)
+			}
+
+			if verifyQuarantine {
+				dest, err := installer.Quarantine(v, verifyForce)
+				if err != nil {
+					fmt.Fprintf(out, "  Could not quarantine: %v\n", err)
+					status.Error = err.Error()
+				} else {
+					fmt.Fprintf(out, "  Quarantined to %s\n", dest)
+					status.Quarantine = dest
+				}
+			}
+			result.Versions = append(result.Versions, status)
+		}
+
+		if err := printResult(result); err != nil {
+			return err
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d version(s) failed verification", failed, len(versions))
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	verifyCmd.Flags().BoolVar(&verifyAll, "all", false, "verify all installed versions")
+	verifyCmd.Flags().BoolVar(&verifyUpstream, "upstream", false, "also compare with the published checksums")
+	verifyCmd.Flags().BoolVar(&verifyQuarantine, "quarantine", false, "move failing versions to the quarantine directory")
+	verifyCmd.Flags().BoolVar(&verifyForce, "force", false, "quarantine failing versions even when pinned")
+	rootCmd.AddCommand(verifyCmd)
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/germainlefebvre4/kuve/internal/kubectl"
+	"github.com/germainlefebvre4/kuve/internal/version"
+	"github.com/germainlefebvre4/kuve/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// sourceArgument is the 'kuve which' source of a version given on the command line
+const sourceArgument = "argument"
+
+var whichCmd = &cobra.Command{
+	Use:   "which [version]",
+	Short: "Print the path of the kubectl binary kuve would use",
+	Long: `Print the absolute path of the kubectl binary that 'kuve use' would switch
+to in the current directory, without running it: the KUVE_KUBECTL_VERSION
+environment variable, else the nearest project file (.kubernetes-version
+resolved with .kuve.lock, .kuve.yaml, .tool-versions or mise.toml), else the
+global version. With a version argument, the binary of
+that version is printed instead; partial versions (1.28, latest) are
+resolved like 'kuve install'.
+
+The command fails (exit code 2) when the version is not installed, so that
+editors and scripts can fall back or run 'kuve install'.
+
+Example:
+  kuve which
+  kuve which 1.28.3
+  kuve which --output json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		tool := cfg.Tool()
+		manager := version.NewManager(cfg)
+		result := whichResult{Tool: tool.Name}
+
+		if len(args) > 0 {
+			result.Source = sourceArgument
+
+			// Resolve partial versions (1.28, latest) like 'kuve use'
+			result.Version = normalizeVersion(args[0])
+			if !config.IsFullVersion(result.Version) {
+				if result.Version, err = manager.ResolveVersion(cmd.Context(), args[0]); err != nil {
+					return fmt.Errorf("failed to resolve version: %w", err)
+				}
+			}
+			if err := config.ValidateVersion(result.Version); err != nil {
+				return err
+			}
+		} else {
+			selection, resolved, _, err := effectiveVersion(cmd.Context(), cfg, manager, kubectl.CurrentPlatform().String())
+			if err != nil {
+				return err
+			}
+			result.Version = resolved
+			result.Source = selection.Source
+		}
+
+		result.Installed = manager.IsVersionInstalled(result.Version)
+		if result.Installed {
+			result.Path = manager.BinaryPath(result.Version)
+		}
+
+		if structuredOutput() {
+			if err := printResult(result); err != nil {
+				return err
+			}
+		} else if result.Installed {
+			fmt.Println(result.Path)
+		}
+
+		if !result.Installed {
+			return fmt.Errorf("%w: %s %s, run 'kuve install %s'", kubectl.ErrNotInstalled, tool.Name, result.Version, result.Version)
+		}
+		return nil
+	},
+}
+
+var pathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the kuve bin directory",
+	Long: `Print the absolute path of the kuve bin directory, holding the symlink to
+the active kubectl version, to add to PATH or to configure in an editor.
+
+The command fails (exit code 2) when no version is active in the directory.
+
+Example:
+  export PATH="$(kuve path):$PATH"`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		tool := cfg.Tool()
+
+		// Stat follows the symlink, a dangling link is not an active version
+		_, statErr := os.Stat(cfg.CurrentSymlink)
+		result := pathResult{Tool: tool.Name, Path: cfg.BinDir, Installed: statErr == nil}
+
+		if structuredOutput() {
+			if err := printResult(result); err != nil {
+				return err
+			}
+		} else {
+			fmt.Println(result.Path)
+		}
+
+		if !result.Installed {
+			return fmt.Errorf("%w: no %s version is active in %s, run 'kuve use' or 'kuve switch'", kubectl.ErrNotInstalled, tool.Name, cfg.BinDir)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(whichCmd)
+	rootCmd.AddCommand(pathCmd)
+}
@@ -0,0 +1,170 @@
+// Package gitignore matches paths against the patterns of .gitignore files
+package gitignore
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// FileName is the name of the files holding ignore patterns
+const FileName = ".gitignore"
+
+// Matcher holds the patterns of the .gitignore files of a tree. Patterns
+// apply to the paths below the directory of their file, and the last
+// matching pattern decides, so that "!pattern" re-includes paths.
+type Matcher struct {
+	patterns []pattern
+}
+
+// pattern is a parsed .gitignore line
+type pattern struct {
+	// base is the slash separated directory of the .gitignore file, relative
+	// to the root of the tree ("" for the root)
+	base string
+
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+
+	// anchored patterns match the path relative to base, the others the last
+	// path element at any depth
+	anchored bool
+}
+
+// AddFile reads the .gitignore file of a directory, given relative to the
+// root of the tree with slashes ("" for the root). A missing file is ignored.
+func (m *Matcher) AddFile(root, dir string) error {
+	file := filepath.Join(root, filepath.FromSlash(dir), FileName)
+	f, err := os.Open(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		m.AddPattern(dir, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	return nil
+}
+
+// AddPattern adds a .gitignore line applying below the directory dir
+func (m *Matcher) AddPattern(dir, line string) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return
+	}
+
+	p := pattern{base: dir}
+	if strings.HasPrefix(line, "!") {
+		p.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\`) {
+		// Escaped leading "#" or "!"
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		p.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return
+	}
+
+	// A slash at the beginning or in the middle anchors the pattern
+	if strings.Contains(line, "/") {
+		p.anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+
+	re, err := regexp.Compile("^" + patternRegex(line) + "$")
+	if err != nil {
+		// Invalid patterns are ignored, like git does
+		return
+	}
+	p.re = re
+	m.patterns = append(m.patterns, p)
+}
+
+// patternRegex translates a glob pattern into a regular expression
+func patternRegex(glob string) string {
+	var b strings.Builder
+	for idx := 0; idx < len(glob); idx++ {
+		c := glob[idx]
+		switch {
+		case strings.HasPrefix(glob[idx:], "**/"):
+			// Zero or more directories
+			b.WriteString("(?:.*/)?")
+			idx += 2
+		case strings.HasPrefix(glob[idx:], "/**") && idx+3 == len(glob):
+			// Everything inside
+			b.WriteString("/.*")
+			idx += 2
+		case strings.HasPrefix(glob[idx:], "**"):
+			b.WriteString(".*")
+			idx++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[idx+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := glob[idx+1 : idx+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			idx += end + 1
+		case c == '\\' && idx+1 < len(glob):
+			idx++
+			b.WriteString(regexp.QuoteMeta(string(glob[idx])))
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
+
+// Match reports whether a path, relative to the root of the tree with
+// slashes, is ignored
+func (m *Matcher) Match(relPath string, isDir bool) bool {
+	ignored := false
+	for _, p := range m.patterns {
+		if p.dirOnly && !isDir {
+			continue
+		}
+
+		rel := relPath
+		if p.base != "" {
+			if !strings.HasPrefix(relPath, p.base+"/") {
+				continue
+			}
+			rel = relPath[len(p.base)+1:]
+		}
+
+		subject := rel
+		if !p.anchored {
+			subject = path.Base(rel)
+		}
+		if p.re.MatchString(subject) {
+			ignored = !p.negate
+		}
+	}
+	return ignored
+}
@@ -0,0 +1,80 @@
+package gitignore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	var m Matcher
+	for _, line := range []string{
+		"# comment",
+		"",
+		"*.log",
+		"!keep.log",
+		"build/",
+		"/vendor",
+		"docs/**/generated",
+		"tmp?",
+		`\#notes`,
+	} {
+		m.AddPattern("", line)
+	}
+	m.AddPattern("services/api", "fixtures")
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{path: "app.log", want: true},
+		{path: "logs/app.log", want: true},
+		{path: "logs/keep.log", want: false},
+		{path: "build", isDir: true, want: true},
+		{path: "src/build", isDir: true, want: true},
+		{path: "build", isDir: false, want: false},
+		{path: "vendor", isDir: true, want: true},
+		{path: "src/vendor", isDir: true, want: false},
+		{path: "docs/generated", isDir: true, want: true},
+		{path: "docs/a/b/generated", isDir: true, want: true},
+		{path: "tmp1", isDir: true, want: true},
+		{path: "tmp12", isDir: true, want: false},
+		{path: "#notes", want: true},
+		{path: "services/api/fixtures", isDir: true, want: true},
+		{path: "services/web/fixtures", isDir: true, want: false},
+		{path: "fixtures", isDir: true, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := m.Match(tt.path, tt.isDir); got != tt.want {
+			t.Errorf("Match(%q, %v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+		}
+	}
+}
+
+func TestAddFile(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "kuve-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := os.MkdirAll(filepath.Join(tmpDir, "sub"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "sub", FileName), []byte("node_modules/\n"), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", FileName, err)
+	}
+
+	var m Matcher
+	if err := m.AddFile(tmpDir, ""); err != nil {
+		t.Errorf("AddFile() without file error = %v", err)
+	}
+	if err := m.AddFile(tmpDir, "sub"); err != nil {
+		t.Fatalf("AddFile() error = %v", err)
+	}
+	if !m.Match("sub/node_modules", true) || m.Match("node_modules", true) {
+		t.Errorf("Patterns of sub/%s must only apply below sub", FileName)
+	}
+}
@@ -0,0 +1,145 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/germainlefebvre4/kuve/pkg/config"
+)
+
+// New creates the HTTP client shared by kubectl downloads and release queries.
+// It honours the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables,
+// trusts the system roots plus the configured CA bundle, presents the
+// configured client certificate and authenticates requests per host using
+// the configured bearer tokens or the .netrc file. The .netrc default entry
+// only applies to the hosts of the download mirrors.
+func New(settings config.HTTPSettings, mirrors []config.Mirror) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+
+	tlsConfig, err := newTLSConfig(settings)
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	netrcPath := settings.Netrc
+	if netrcPath == "" {
+		netrcPath = defaultNetrcPath()
+	}
+	machines, err := readNetrc(netrcPath)
+	if err != nil {
+		return nil, err
+	}
+
+	mirrorHosts := map[string]bool{}
+	for _, mirror := range mirrors {
+		if u, err := url.Parse(mirror.URL); err == nil && u.Hostname() != "" {
+			mirrorHosts[strings.ToLower(u.Hostname())] = true
+		}
+	}
+
+	return &http.Client{
+		Transport: &authTransport{
+			base:        transport,
+			hosts:       settings.Auth,
+			machines:    machines,
+			mirrorHosts: mirrorHosts,
+		},
+		CheckRedirect: checkRedirect,
+	}, nil
+}
+
+// maxRedirects is the number of redirects followed, like the default client
+const maxRedirects = 10
+
+// checkRedirect drops the credentials of a request redirected to another host
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return errors.New("stopped after 10 redirects")
+	}
+	if !strings.EqualFold(req.URL.Host, via[len(via)-1].URL.Host) {
+		req.Header.Del("Authorization")
+	}
+	return nil
+}
+
+// newTLSConfig builds the TLS configuration from the CA bundle and client certificate settings
+func newTLSConfig(settings config.HTTPSettings) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if settings.CABundle != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+
+		data, err := os.ReadFile(settings.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificate found in CA bundle %s", settings.CABundle)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if settings.ClientCert != "" || settings.ClientKey != "" {
+		if settings.ClientCert == "" || settings.ClientKey == "" {
+			return nil, fmt.Errorf("both clientCert and clientKey must be set for client certificate authentication")
+		}
+		cert, err := tls.LoadX509KeyPair(settings.ClientCert, settings.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// authTransport adds credentials to https requests based on their host.
+// Requests redirected from another host are sent without credentials.
+type authTransport struct {
+	base        http.RoundTripper
+	hosts       map[string]config.HostAuth
+	machines    map[string]netrcMachine
+	mirrorHosts map[string]bool
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Authorization") != "" || req.URL.Scheme != "https" {
+		return t.base.RoundTrip(req)
+	}
+	if req.Response != nil && !strings.EqualFold(req.Response.Request.URL.Host, req.URL.Host) {
+		return t.base.RoundTrip(req)
+	}
+
+	host := strings.ToLower(req.URL.Hostname())
+
+	if auth, ok := t.hosts[host]; ok {
+		if token := auth.Token(); token != "" {
+			req = req.Clone(req.Context())
+			req.Header.Set("Authorization", "Bearer "+token)
+			return t.base.RoundTrip(req)
+		}
+	}
+
+	machine, ok := t.machines[host]
+	if !ok && t.mirrorHosts[host] {
+		machine, ok = t.machines[""]
+	}
+	if ok && machine.login != "" {
+		req = req.Clone(req.Context())
+		req.SetBasicAuth(machine.login, machine.password)
+	}
+
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,234 @@
+package httpclient
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/germainlefebvre4/kuve/pkg/config"
+)
+
+func TestParseNetrc(t *testing.T) {
+	data := `# corporate mirror
+machine Mirror.Example.com login alice password s3cret
+machine other.example.com
+  login bob
+  password hunter2
+
+macdef init
+cd /pub
+quit
+
+default login anonymous password guest
+`
+
+	machines := parseNetrc(data)
+
+	tests := []struct {
+		host     string
+		login    string
+		password string
+	}{
+		{host: "mirror.example.com", login: "alice", password: "s3cret"},
+		{host: "other.example.com", login: "bob", password: "hunter2"},
+		{host: "", login: "anonymous", password: "guest"},
+	}
+
+	for _, tt := range tests {
+		got, ok := machines[tt.host]
+		if !ok {
+			t.Errorf("machine %q not found", tt.host)
+			continue
+		}
+		if got.login != tt.login || got.password != tt.password {
+			t.Errorf("machine %q = %+v, want %s/%s", tt.host, got, tt.login, tt.password)
+		}
+	}
+}
+
+// writeCABundle writes the certificate of a TLS test server to a CA bundle
+func writeCABundle(t *testing.T, dir string, server *httptest.Server) string {
+	t.Helper()
+	caBundle := filepath.Join(dir, "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caBundle, certPEM, 0644); err != nil {
+		t.Fatalf("Failed to write CA bundle: %v", err)
+	}
+	return caBundle
+}
+
+// getAuthorization sends a GET request and returns the Authorization header received by the server
+func getAuthorization(t *testing.T, client *http.Client, url string, authorization *string) string {
+	t.Helper()
+	*authorization = ""
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+	return *authorization
+}
+
+func TestNewAddsCredentialsPerHost(t *testing.T) {
+	var authorization string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	tmpDir, err := os.MkdirTemp("", "kuve-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	caBundle := writeCABundle(t, tmpDir, server)
+	netrcPath := filepath.Join(tmpDir, "netrc")
+	os.WriteFile(netrcPath, []byte("machine 127.0.0.1 login alice password s3cret\n"), 0600)
+
+	// .netrc credentials
+	client, err := New(config.HTTPSettings{CABundle: caBundle, Netrc: netrcPath}, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got := getAuthorization(t, client, server.URL, &authorization); got != "Basic YWxpY2U6czNjcmV0" {
+		t.Errorf("Authorization = %q, want basic auth from netrc", got)
+	}
+
+	// Bearer token takes precedence over .netrc
+	t.Setenv("KUVE_TEST_TOKEN", "from-env")
+	client, err = New(config.HTTPSettings{
+		CABundle: caBundle,
+		Netrc:    netrcPath,
+		Auth: map[string]config.HostAuth{
+			"127.0.0.1": {BearerToken: "from-config", BearerTokenEnv: "KUVE_TEST_TOKEN"},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got := getAuthorization(t, client, server.URL, &authorization); got != "Bearer from-env" {
+		t.Errorf("Authorization = %q, want bearer token", got)
+	}
+}
+
+func TestNewRestrictsCredentials(t *testing.T) {
+	var authorization string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	})
+	server := httptest.NewTLSServer(handler)
+	defer server.Close()
+	plainServer := httptest.NewServer(handler)
+	defer plainServer.Close()
+
+	// The redirecting server shares the certificate of the target server
+	redirector := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, server.URL, http.StatusFound)
+	}))
+	redirector.TLS = server.TLS
+	redirector.StartTLS()
+	defer redirector.Close()
+
+	tmpDir, err := os.MkdirTemp("", "kuve-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	caBundle := writeCABundle(t, tmpDir, server)
+	netrcPath := filepath.Join(tmpDir, "netrc")
+
+	// The default entry only applies to the mirror hosts
+	os.WriteFile(netrcPath, []byte("default login alice password s3cret\n"), 0600)
+	client, err := New(config.HTTPSettings{CABundle: caBundle, Netrc: netrcPath}, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got := getAuthorization(t, client, server.URL, &authorization); got != "" {
+		t.Errorf("Authorization = %q, want no default credentials outside the mirrors", got)
+	}
+	client, err = New(config.HTTPSettings{CABundle: caBundle, Netrc: netrcPath}, []config.Mirror{{URL: server.URL + "/release"}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got := getAuthorization(t, client, server.URL, &authorization); got != "Basic YWxpY2U6czNjcmV0" {
+		t.Errorf("Authorization = %q, want default credentials for the mirror", got)
+	}
+
+	// Credentials are never sent over plain http, nor after a cross-host redirect
+	client, err = New(config.HTTPSettings{
+		CABundle: caBundle,
+		Netrc:    netrcPath,
+		Auth:     map[string]config.HostAuth{"127.0.0.1": {BearerToken: "s3cret"}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got := getAuthorization(t, client, plainServer.URL, &authorization); got != "" {
+		t.Errorf("Authorization = %q, want no credentials over http", got)
+	}
+	if got := getAuthorization(t, client, redirector.URL, &authorization); got != "" {
+		t.Errorf("Authorization = %q, want no credentials after a cross-host redirect", got)
+	}
+}
+
+func TestNewTrustsCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	tmpDir, err := os.MkdirTemp("", "kuve-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// Without the CA bundle the self-signed certificate is rejected
+	client, err := New(config.HTTPSettings{Netrc: filepath.Join(tmpDir, "missing")}, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if resp, err := client.Get(server.URL); err == nil {
+		resp.Body.Close()
+		t.Fatalf("Expected an unknown authority error")
+	}
+
+	caBundle := writeCABundle(t, tmpDir, server)
+
+	client, err = New(config.HTTPSettings{CABundle: caBundle, Netrc: filepath.Join(tmpDir, "missing")}, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() with CA bundle error = %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestNewInvalidSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings config.HTTPSettings
+	}{
+		{
+			name:     "missing CA bundle",
+			settings: config.HTTPSettings{CABundle: "/nonexistent/ca.pem"},
+		},
+		{
+			name:     "client certificate without key",
+			settings: config.HTTPSettings{ClientCert: "/nonexistent/cert.pem"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.settings, nil); err == nil {
+				t.Errorf("Expected New() to fail")
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package httpclient
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// netrcMachine holds the credentials of a .netrc machine entry
+type netrcMachine struct {
+	login    string
+	password string
+}
+
+// defaultNetrcPath returns the .netrc location, honouring the NETRC environment variable
+func defaultNetrcPath() string {
+	if path := os.Getenv("NETRC"); path != "" {
+		return path
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	name := ".netrc"
+	if runtime.GOOS == "windows" {
+		name = "_netrc"
+	}
+	return filepath.Join(homeDir, name)
+}
+
+// readNetrc reads the machine entries of a .netrc file.
+// A missing file yields no entries. The "default" entry is stored under the empty host.
+func readNetrc(path string) (map[string]netrcMachine, error) {
+	machines := map[string]netrcMachine{}
+	if path == "" {
+		return machines, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return machines, nil
+		}
+		return nil, fmt.Errorf("failed to read netrc file: %w", err)
+	}
+
+	return parseNetrc(string(data)), nil
+}
+
+// parseNetrc parses the content of a .netrc file
+func parseNetrc(data string) map[string]netrcMachine {
+	machines := map[string]netrcMachine{}
+
+	var (
+		host    string
+		current *netrcMachine
+	)
+	flush := func() {
+		if current != nil {
+			if _, exists := machines[host]; !exists {
+				machines[host] = *current
+			}
+		}
+		current = nil
+	}
+
+	lines := strings.Split(data, "\n")
+	for l := 0; l < len(lines); l++ {
+		fields := strings.Fields(lines[l])
+		for f := 0; f < len(fields); f++ {
+			if strings.HasPrefix(fields[f], "#") {
+				break
+			}
+
+			switch fields[f] {
+			case "machine":
+				flush()
+				if f+1 < len(fields) {
+					f++
+					host = strings.ToLower(fields[f])
+					current = &netrcMachine{}
+				}
+			case "default":
+				flush()
+				host = ""
+				current = &netrcMachine{}
+			case "login", "password":
+				if current == nil || f+1 >= len(fields) {
+					continue
+				}
+				if fields[f] == "login" {
+					current.login = fields[f+1]
+				} else {
+					current.password = fields[f+1]
+				}
+				f++
+			case "macdef":
+				// Macro definitions run until the next empty line
+				flush()
+				for l+1 < len(lines) && strings.TrimSpace(lines[l+1]) != "" {
+					l++
+				}
+				f = len(fields)
+			}
+		}
+	}
+	flush()
+
+	return machines
+}
@@ -0,0 +1,71 @@
+package kubectl
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// isTarGz reports whether a file name looks like a gzipped tarball
+func isTarGz(name string) bool {
+	return strings.HasSuffix(name, ".tar.gz") || strings.HasSuffix(name, ".tgz")
+}
+
+// extractBinary streams a gzipped tarball and writes the regular file whose
+// base name is binaryName (e.g. kubernetes/client/bin/kubectl) to destPath.
+// Entries with absolute paths or ".." components are rejected.
+func extractBinary(r io.Reader, binaryName, destPath string) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("failed to read gzip archive: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tar archive: %w", err)
+		}
+
+		if err := checkArchivePath(header.Name); err != nil {
+			return err
+		}
+
+		if header.Typeflag != tar.TypeReg || path.Base(header.Name) != binaryName {
+			continue
+		}
+
+		out, err := os.OpenFile(destPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, tr); err != nil {
+			out.Close()
+			return fmt.Errorf("failed to extract %s: %w", header.Name, err)
+		}
+		return out.Close()
+	}
+
+	return fmt.Errorf("%s not found in archive", binaryName)
+}
+
+// checkArchivePath rejects archive entries that could escape the extraction directory
+func checkArchivePath(name string) error {
+	if strings.HasPrefix(name, "/") || strings.Contains(name, "\\") {
+		return fmt.Errorf("unsafe path %q in archive", name)
+	}
+	for _, part := range strings.Split(name, "/") {
+		if part == ".." {
+			return fmt.Errorf("unsafe path %q in archive", name)
+		}
+	}
+	return nil
+}
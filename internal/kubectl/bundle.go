@@ -0,0 +1,409 @@
+package kubectl
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/germainlefebvre4/kuve/pkg/config"
+)
+
+const (
+	// BundleManifestName is the name of the manifest inside a bundle
+	BundleManifestName = "manifest.json"
+
+	// BundleChecksumsName is the name of the sha256sum compatible checksum list inside a bundle
+	BundleChecksumsName = "SHA256SUMS"
+
+	// bundleSchemaVersion is the version of the bundle manifest format
+	bundleSchemaVersion = 1
+)
+
+// BundleManifest describes the content of an air-gap bundle
+type BundleManifest struct {
+	SchemaVersion int           `json:"schemaVersion"`
+	CreatedAt     time.Time     `json:"createdAt"`
+	Entries       []BundleEntry `json:"entries"`
+}
+
+// BundleEntry describes a kubectl binary stored in a bundle
+type BundleEntry struct {
+	Version  string   `json:"version"`
+	Platform Platform `json:"platform"`
+	Path     string   `json:"path"`
+	SHA256   string   `json:"sha256"`
+}
+
+// bundleEntryPath returns the path of a binary inside a bundle
+func bundleEntryPath(version string, platform Platform) string {
+	return path.Join(version, platform.OS+"-"+platform.Arch, platform.BinaryName(config.KubectlBinaryName))
+}
+
+// CreateBundle downloads the given versions for each platform, verified
+// against the published checksums, and writes them to a gzipped tarball at
+// outPath together with a manifest and a SHA256SUMS file.
+// The manifest is the first entry so that bundles can be installed in one pass.
+func (i *Installer) CreateBundle(ctx context.Context, outPath string, versions []string, platforms []Platform) (*BundleManifest, error) {
+	if len(versions) == 0 || len(platforms) == 0 {
+		return nil, fmt.Errorf("at least one version and one platform are required")
+	}
+
+	workDir, err := os.MkdirTemp("", "kuve-bundle-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create working directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	manifest := &BundleManifest{
+		SchemaVersion: bundleSchemaVersion,
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	for _, version := range versions {
+		for _, platform := range platforms {
+			entry, err := i.downloadBundleEntry(ctx, workDir, version, platform)
+			if err != nil {
+				return nil, err
+			}
+			manifest.Entries = append(manifest.Entries, entry)
+		}
+	}
+
+	if err := writeBundle(outPath, workDir, manifest); err != nil {
+		os.Remove(outPath)
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// downloadBundleEntry downloads and verifies one binary into workDir
+func (i *Installer) downloadBundleEntry(ctx context.Context, workDir, version string, platform Platform) (BundleEntry, error) {
+	entryPath := bundleEntryPath(version, platform)
+	destPath := filepath.Join(workDir, filepath.FromSlash(entryPath))
+	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		return BundleEntry{}, err
+	}
+
+	fmt.Fprintf(i.Out, "Downloading kubectl %s for %s...\n", version, platform)
+	if err := i.Download(ctx, version, platform, destPath); err != nil {
+		return BundleEntry{}, fmt.Errorf("failed to download kubectl %s for %s: %w", version, platform, err)
+	}
+
+	// Download already verified the artifact against the published checksums
+	checksum, err := fileSHA256(destPath)
+	if err != nil {
+		return BundleEntry{}, fmt.Errorf("failed to compute checksum: %w", err)
+	}
+
+	return BundleEntry{
+		Version:  version,
+		Platform: platform,
+		Path:     entryPath,
+		SHA256:   checksum,
+	}, nil
+}
+
+// writeBundle writes the manifest, checksums and binaries to a gzipped tarball
+func writeBundle(outPath, workDir string, manifest *BundleManifest) error {
+	out, err := os.Create(outPath)
+	if err != nil {
+		return fmt.Errorf("failed to create bundle: %w", err)
+	}
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeTarFile(tw, BundleManifestName, manifestData, 0644); err != nil {
+		return err
+	}
+
+	var sums strings.Builder
+	for _, entry := range manifest.Entries {
+		fmt.Fprintf(&sums, "%s  %s\n", entry.SHA256, entry.Path)
+	}
+	if err := writeTarFile(tw, BundleChecksumsName, []byte(sums.String()), 0644); err != nil {
+		return err
+	}
+
+	for _, entry := range manifest.Entries {
+		if err := addTarFile(tw, entry.Path, filepath.Join(workDir, filepath.FromSlash(entry.Path))); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return out.Close()
+}
+
+// writeTarFile adds an in-memory file to a tar archive
+func writeTarFile(tw *tar.Writer, name string, data []byte, mode int64) error {
+	header := &tar.Header{
+		Name:     name,
+		Mode:     mode,
+		Size:     int64(len(data)),
+		Typeflag: tar.TypeReg,
+		ModTime:  time.Now(),
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write %s to bundle: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s to bundle: %w", name, err)
+	}
+	return nil
+}
+
+// addTarFile adds a file from disk to a tar archive
+func addTarFile(tw *tar.Writer, name, srcPath string) error {
+	f, err := os.Open(srcPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	header := &tar.Header{
+		Name:     name,
+		Mode:     0755,
+		Size:     info.Size(),
+		Typeflag: tar.TypeReg,
+		ModTime:  info.ModTime(),
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write %s to bundle: %w", name, err)
+	}
+	if _, err := io.Copy(tw, f); err != nil {
+		return fmt.Errorf("failed to write %s to bundle: %w", name, err)
+	}
+	return nil
+}
+
+// BundleInstallResult holds the outcome of installing one bundle entry
+type BundleInstallResult struct {
+	Version string
+	Skipped bool // already installed
+	Err     error
+}
+
+// InstallBundle verifies and installs the entries of a bundle that match the
+// given platform. Entries for other platforms are ignored. The checksums of
+// the manifest must agree with the SHA256SUMS list, and each binary is
+// verified against them.
+func (i *Installer) InstallBundle(ctx context.Context, bundlePath string, platform Platform) ([]BundleInstallResult, error) {
+	f, err := os.Open(bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bundle: %w", err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+
+	manifest, err := readBundleManifest(tr)
+	if err != nil {
+		return nil, err
+	}
+	sums, err := readBundleChecksums(tr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Index the entries to install by their path in the bundle
+	wanted := map[string]BundleEntry{}
+	for _, entry := range manifest.Entries {
+		if entry.Platform == platform {
+			if err := checkArchivePath(entry.Path); err != nil {
+				return nil, err
+			}
+			if err := config.ValidateVersion(entry.Version); err != nil {
+				return nil, fmt.Errorf("invalid bundle manifest: %w", err)
+			}
+			if sum, ok := sums[entry.Path]; !ok || !strings.EqualFold(sum, entry.SHA256) {
+				return nil, fmt.Errorf("invalid bundle: the checksum of %s in %s does not match the manifest", entry.Path, BundleChecksumsName)
+			}
+			wanted[entry.Path] = entry
+		}
+	}
+	if len(wanted) == 0 {
+		return nil, fmt.Errorf("bundle contains no kubectl for %s", platform)
+	}
+
+	results := map[string]BundleInstallResult{}
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+		if err := checkArchivePath(header.Name); err != nil {
+			return nil, err
+		}
+
+		entry, ok := wanted[header.Name]
+		if !ok || header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		results[entry.Path] = i.installBundleEntry(tr, entry, platform)
+	}
+
+	var ordered []BundleInstallResult
+	paths := make([]string, 0, len(wanted))
+	for p := range wanted {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		result, ok := results[p]
+		if !ok {
+			result = BundleInstallResult{Version: wanted[p].Version, Err: fmt.Errorf("%s listed in manifest but missing from bundle", p)}
+		}
+		ordered = append(ordered, result)
+	}
+
+	return ordered, nil
+}
+
+// readBundleManifest reads the manifest, which must be the first bundle entry
+func readBundleManifest(tr *tar.Reader) (*BundleManifest, error) {
+	header, err := tr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+	if header.Name != BundleManifestName {
+		return nil, fmt.Errorf("invalid bundle: %s must be the first entry", BundleManifestName)
+	}
+
+	var manifest BundleManifest
+	if err := json.NewDecoder(io.LimitReader(tr, 10<<20)).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("invalid bundle manifest: %w", err)
+	}
+	if manifest.SchemaVersion != bundleSchemaVersion {
+		return nil, fmt.Errorf("unsupported bundle schema version %d", manifest.SchemaVersion)
+	}
+
+	return &manifest, nil
+}
+
+// readBundleChecksums reads the SHA256SUMS list, which must follow the
+// manifest, and returns the checksums keyed by path
+func readBundleChecksums(tr *tar.Reader) (map[string]string, error) {
+	header, err := tr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+	if header.Name != BundleChecksumsName {
+		return nil, fmt.Errorf("invalid bundle: %s must follow %s", BundleChecksumsName, BundleManifestName)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(tr, 10<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", BundleChecksumsName, err)
+	}
+
+	sums := map[string]string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid bundle: malformed %s line %q", BundleChecksumsName, line)
+		}
+		sums[strings.TrimPrefix(fields[1], "*")] = fields[0]
+	}
+	return sums, nil
+}
+
+// installBundleEntry extracts one binary, verifies its checksum and installs it
+func (i *Installer) installBundleEntry(r io.Reader, entry BundleEntry, platform Platform) BundleInstallResult {
+	result := BundleInstallResult{Version: entry.Version}
+	binaryName := platform.BinaryName(config.KubectlBinaryName)
+
+	versionDir := filepath.Join(i.config.VersionsDir, entry.Version)
+	if _, err := os.Stat(filepath.Join(versionDir, binaryName)); err == nil {
+		result.Skipped = true
+		return result
+	}
+
+	stagingDir, err := os.MkdirTemp(i.config.VersionsDir, ".bundle-*")
+	if err != nil {
+		result.Err = fmt.Errorf("failed to create staging directory: %w", err)
+		return result
+	}
+	defer os.RemoveAll(stagingDir)
+
+	stagedPath := filepath.Join(stagingDir, binaryName)
+	out, err := os.OpenFile(stagedPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(out, h), r)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		result.Err = fmt.Errorf("failed to extract %s: %w", entry.Path, err)
+		return result
+	}
+
+	if actual := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(actual, entry.SHA256) {
+		result.Err = &ChecksumError{Path: entry.Path, Expected: entry.SHA256, Actual: actual}
+		return result
+	}
+
+	if err := recordMetadata(stagingDir, platform, []string{binaryName}); err != nil {
+		result.Err = err
+		return result
+	}
+
+	if err := os.Chmod(stagingDir, 0755); err != nil {
+		result.Err = err
+		return result
+	}
+	if err := os.Rename(stagingDir, versionDir); err != nil {
+		result.Err = fmt.Errorf("failed to install version directory: %w", err)
+	}
+
+	return result
+}
@@ -0,0 +1,181 @@
+package kubectl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/germainlefebvre4/kuve/pkg/config"
+)
+
+func TestBundleRoundTrip(t *testing.T) {
+	server := newReleaseServer(t)
+
+	cfg := newTestConfig(t)
+	cfg.Settings.Download.Mirrors = []config.Mirror{{URL: server.URL}}
+	installer := NewInstaller(cfg)
+	installer.Quiet = true
+
+	linuxAMD64 := Platform{OS: "linux", Arch: "amd64"}
+	linuxARM64 := Platform{OS: "linux", Arch: "arm64"}
+
+	bundlePath := filepath.Join(cfg.KuveDir, "bundle.tar.gz")
+	manifest, err := installer.CreateBundle(context.Background(), bundlePath,
+		[]string{"v1.27.16", "v1.28.3"}, []Platform{linuxAMD64, linuxARM64})
+	if err != nil {
+		t.Fatalf("CreateBundle() error = %v", err)
+	}
+	if len(manifest.Entries) != 4 {
+		t.Fatalf("Expected 4 bundle entries, got %d", len(manifest.Entries))
+	}
+
+	results, err := installer.InstallBundle(context.Background(), bundlePath, linuxARM64)
+	if err != nil {
+		t.Fatalf("InstallBundle() error = %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 install results, got %d", len(results))
+	}
+	for _, result := range results {
+		if result.Err != nil || result.Skipped {
+			t.Errorf("Unexpected result %+v", result)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(cfg.VersionsDir, "v1.28.3", config.KubectlBinaryName))
+	if err != nil {
+		t.Fatalf("Installed kubectl not found: %v", err)
+	}
+	if string(data) != "kubectl v1.28.3 linux/arm64" {
+		t.Errorf("Installed the wrong binary: %q", data)
+	}
+
+	// Installing again skips the existing versions
+	results, err = installer.InstallBundle(context.Background(), bundlePath, linuxARM64)
+	if err != nil {
+		t.Fatalf("InstallBundle() error = %v", err)
+	}
+	for _, result := range results {
+		if !result.Skipped {
+			t.Errorf("Expected %s to be skipped", result.Version)
+		}
+	}
+
+	// A platform missing from the bundle is reported
+	if _, err := installer.InstallBundle(context.Background(), bundlePath, Platform{OS: "darwin", Arch: "arm64"}); err == nil {
+		t.Errorf("Expected InstallBundle() to fail for a missing platform")
+	}
+}
+
+func TestInstallBundleChecksumMismatch(t *testing.T) {
+	cfg := newTestConfig(t)
+	installer := NewInstaller(cfg)
+
+	platform := Platform{OS: "linux", Arch: "amd64"}
+	entryPath := bundleEntryPath("v1.28.3", platform)
+	manifest := fmt.Sprintf(`{"schemaVersion":1,"entries":[{"version":"v1.28.3","platform":{"os":"linux","arch":"amd64"},"path":%q,"sha256":%q}]}`,
+		entryPath, strings.Repeat("0", 64))
+
+	bundlePath := filepath.Join(cfg.KuveDir, "bundle.tar.gz")
+	writeOrderedTarGz(t, bundlePath, [][2]string{
+		{BundleManifestName, manifest},
+		{BundleChecksumsName, strings.Repeat("0", 64) + "  " + entryPath + "\n"},
+		{entryPath, "tampered kubectl"},
+	})
+
+	results, err := installer.InstallBundle(context.Background(), bundlePath, platform)
+	if err != nil {
+		t.Fatalf("InstallBundle() error = %v", err)
+	}
+	if len(results) != 1 || !errors.Is(results[0].Err, ErrChecksumMismatch) {
+		t.Fatalf("Expected a checksum mismatch, got %+v", results)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.VersionsDir, "v1.28.3")); !os.IsNotExist(err) {
+		t.Errorf("Tampered version must not be installed")
+	}
+}
+
+func TestInstallBundleRejectsInvalidBundles(t *testing.T) {
+	platform := Platform{OS: "linux", Arch: "amd64"}
+	entryPath := bundleEntryPath("v1.28.3", platform)
+	checksum := strings.Repeat("a", 64)
+	manifestFor := func(version, path string) string {
+		return fmt.Sprintf(`{"schemaVersion":1,"entries":[{"version":%q,"platform":{"os":"linux","arch":"amd64"},"path":%q,"sha256":%q}]}`,
+			version, path, checksum)
+	}
+
+	tests := []struct {
+		name  string
+		files [][2]string
+	}{
+		{
+			name: "missing checksum list",
+			files: [][2]string{
+				{BundleManifestName, manifestFor("v1.28.3", entryPath)},
+				{entryPath, "kubectl"},
+			},
+		},
+		{
+			name: "checksum list disagreeing with the manifest",
+			files: [][2]string{
+				{BundleManifestName, manifestFor("v1.28.3", entryPath)},
+				{BundleChecksumsName, strings.Repeat("b", 64) + "  " + entryPath + "\n"},
+				{entryPath, "kubectl"},
+			},
+		},
+		{
+			name: "version escaping the versions directory",
+			files: [][2]string{
+				{BundleManifestName, manifestFor("v1/../../x", "x/kubectl")},
+				{BundleChecksumsName, checksum + "  x/kubectl\n"},
+				{"x/kubectl", "kubectl"},
+			},
+		},
+		{
+			name: "version that is not a full version",
+			files: [][2]string{
+				{BundleManifestName, manifestFor("v1.28.3-dirty", "x/kubectl")},
+				{BundleChecksumsName, checksum + "  x/kubectl\n"},
+				{"x/kubectl", "kubectl"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestConfig(t)
+			installer := NewInstaller(cfg)
+
+			bundlePath := filepath.Join(cfg.KuveDir, "bundle.tar.gz")
+			writeOrderedTarGz(t, bundlePath, tt.files)
+
+			if _, err := installer.InstallBundle(context.Background(), bundlePath, platform); err == nil {
+				t.Errorf("Expected InstallBundle() to fail")
+			}
+			entries, _ := os.ReadDir(cfg.VersionsDir)
+			if len(entries) != 0 {
+				t.Errorf("Expected nothing installed, got %d entries", len(entries))
+			}
+		})
+	}
+}
+
+func TestParsePlatforms(t *testing.T) {
+	platforms, err := ParsePlatforms("linux/amd64, linux/arm64")
+	if err != nil {
+		t.Fatalf("ParsePlatforms() error = %v", err)
+	}
+	if len(platforms) != 2 || platforms[1] != (Platform{OS: "linux", Arch: "arm64"}) {
+		t.Errorf("ParsePlatforms() = %v", platforms)
+	}
+
+	for _, invalid := range []string{"linux", "linux/", "/amd64", "linux/amd64/v8"} {
+		if _, err := ParsePlatform(invalid); err == nil {
+			t.Errorf("ParsePlatform(%q) should fail", invalid)
+		}
+	}
+}
@@ -0,0 +1,309 @@
+package kubectl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// errUnexpectedRange is returned when a resumed download does not continue the partial file
+var errUnexpectedRange = errors.New("server returned an unexpected range")
+
+// httpStatusError is returned when the server answers with an unexpected status
+type httpStatusError struct {
+	StatusCode int
+	RetryAfter time.Duration
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("HTTP %d", e.StatusCode)
+}
+
+// Is classifies the status: 404 as ErrNotFound, any other as ErrNetwork
+func (e *httpStatusError) Is(target error) bool {
+	if e.StatusCode == http.StatusNotFound {
+		return target == ErrNotFound
+	}
+	return target == ErrNetwork
+}
+
+// downloadFile downloads a file from a URL and saves it to destPath,
+// reporting progress under the given label unless the installer is quiet.
+// Network errors and 5xx/429 responses are retried with exponential backoff,
+// resuming the partial download when the server supports range requests.
+func (i *Installer) downloadFile(ctx context.Context, url, destPath, label string) error {
+	if i.clientErr != nil {
+		return fmt.Errorf("invalid HTTP configuration: %w", i.clientErr)
+	}
+
+	settings := i.config.Settings.Download
+	partPath := destPath + ".part"
+
+	var lastErr error
+	for attempt := 0; attempt <= settings.Retries; attempt++ {
+		if attempt > 0 {
+			delay := retryDelay(attempt, settings.RetryBackoff, settings.MaxRetryBackoff)
+			var statusErr *httpStatusError
+			if errors.As(lastErr, &statusErr) && statusErr.RetryAfter > delay {
+				delay = statusErr.RetryAfter
+			}
+			fmt.Fprintf(os.Stderr, "%s: %v, retrying in %s (%d/%d)\n",
+				label, lastErr, delay.Round(time.Millisecond), attempt, settings.Retries)
+			if err := sleepContext(ctx, delay); err != nil {
+				lastErr = err
+				break
+			}
+		}
+
+		lastErr = i.downloadAttempt(ctx, url, partPath, label)
+		if lastErr == nil {
+			return os.Rename(partPath, destPath)
+		}
+		if ctx.Err() != nil {
+			lastErr = ctx.Err()
+			break
+		}
+		if !isRetryable(lastErr) {
+			break
+		}
+	}
+
+	os.Remove(partPath)
+	return lastErr
+}
+
+// downloadAttempt downloads url into partPath, resuming from the bytes
+// already present in partPath when the server honours the Range header
+func (i *Installer) downloadAttempt(ctx context.Context, url, partPath, label string) error {
+	var offset int64
+	if info, err := os.Stat(partPath); err == nil {
+		offset = info.Size()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	client := *i.client
+	client.Timeout = i.config.Settings.Download.Timeout
+	resp, err := client.Do(req)
+	if err != nil {
+		return &kindError{kind: ErrNetwork, err: err}
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	total := resp.ContentLength
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0 && contentRangeStart(resp) == offset:
+		flags |= os.O_APPEND
+		if total >= 0 {
+			total += offset
+		}
+	case resp.StatusCode == http.StatusOK:
+		// The server ignored the range request: start over
+		flags |= os.O_TRUNC
+		offset = 0
+	case resp.StatusCode == http.StatusPartialContent:
+		// The returned range does not match the partial file, start over
+		os.Remove(partPath)
+		return errUnexpectedRange
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		// The partial file is unusable, drop it and let the retry start over
+		os.Remove(partPath)
+		return &httpStatusError{StatusCode: resp.StatusCode}
+	default:
+		return &httpStatusError{
+			StatusCode: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+
+	out, err := os.OpenFile(partPath, flags, 0644)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if i.Quiet {
+		_, err = io.Copy(out, resp.Body)
+		return copyError(err)
+	}
+
+	progress := newProgressWriter(os.Stderr, label, total, i.interactive)
+	progress.resumeFrom(offset)
+	if _, err := io.Copy(out, io.TeeReader(resp.Body, progress)); err != nil {
+		progress.Abort()
+		return copyError(err)
+	}
+	progress.Finish()
+
+	return nil
+}
+
+// fetchChecksum downloads a checksum file and returns the hex encoded checksum.
+// Both bare checksums and "<checksum>  <file>" lines are accepted; in a list
+// of several files the entry of fileName is used.
+func (i *Installer) fetchChecksum(ctx context.Context, url, fileName string) (string, error) {
+	body, err := i.fetchSmall(ctx, url, "checksum")
+	if err != nil {
+		return "", err
+	}
+
+	checksum, err := selectChecksum(string(body), fileName)
+	if err != nil {
+		return "", fmt.Errorf("%w at %s", err, url)
+	}
+	return checksum, nil
+}
+
+// fetchSmall downloads a small release file (checksum, signature, certificate)
+// of at most 1 MiB. what names the file in error messages.
+func (i *Installer) fetchSmall(ctx context.Context, url, what string) ([]byte, error) {
+	if i.clientErr != nil {
+		return nil, fmt.Errorf("invalid HTTP configuration: %w", i.clientErr)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := *i.client
+	client.Timeout = i.config.Settings.Timeouts.HTTP
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errorf(ErrNetwork, "failed to fetch %s: %w", what, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: %w", what, &httpStatusError{StatusCode: resp.StatusCode})
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, errorf(ErrNetwork, "failed to read %s: %w", what, err)
+	}
+	return body, nil
+}
+
+// selectChecksum returns the checksum of fileName from the content of a
+// checksum file. A file holding a single checksum applies to any file;
+// a sha256sum style list is searched for the entry of fileName.
+func selectChecksum(data, fileName string) (string, error) {
+	var lines [][]string
+	for _, line := range strings.Split(data, "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			lines = append(lines, fields)
+		}
+	}
+
+	if len(lines) == 0 {
+		return "", fmt.Errorf("empty checksum file")
+	}
+	if len(lines) == 1 {
+		return strings.ToLower(lines[0][0]), nil
+	}
+
+	for _, fields := range lines {
+		// sha256sum marks binary mode entries with a leading '*'
+		if len(fields) >= 2 && strings.TrimPrefix(fields[1], "*") == fileName {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("no checksum for %s", fileName)
+}
+
+// copyError classifies an error copying a response body to a file: failing
+// to write the file is a local error, failing to read the body a network failure
+func copyError(err error) error {
+	var pathErr *os.PathError
+	if err == nil || errors.As(err, &pathErr) {
+		return err
+	}
+	return &kindError{kind: ErrNetwork, err: err}
+}
+
+// isRetryable reports whether a failed download attempt should be retried.
+// Network errors, server errors and rate limiting are considered transient.
+func isRetryable(err error) bool {
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500 ||
+			statusErr.StatusCode == http.StatusTooManyRequests ||
+			statusErr.StatusCode == http.StatusRequestedRangeNotSatisfiable
+	}
+
+	// Local file system errors will not go away by retrying
+	var pathErr *os.PathError
+	return !errors.As(err, &pathErr)
+}
+
+// retryDelay returns the exponential backoff delay for a retry attempt,
+// with jitter so that parallel downloads do not retry in lockstep
+func retryDelay(attempt int, base, maxDelay time.Duration) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+
+	delay := base << (attempt - 1)
+	if delay <= 0 || (maxDelay > 0 && delay > maxDelay) {
+		delay = maxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	// Random delay in [delay/2, delay)
+	half := delay / 2
+	return half + rand.N(delay-half)
+}
+
+// sleepContext waits for the given delay unless the context is cancelled first
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// contentRangeStart returns the first byte position of a Content-Range header
+func contentRangeStart(resp *http.Response) int64 {
+	// Content-Range: bytes 100-999/1000
+	value := strings.TrimPrefix(resp.Header.Get("Content-Range"), "bytes ")
+	start, _, found := strings.Cut(value, "-")
+	if !found {
+		return -1
+	}
+	n, err := strconv.ParseInt(start, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// parseRetryAfter parses a Retry-After header expressed in seconds
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,244 @@
+package kubectl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestDownloader(t *testing.T) *Installer {
+	t.Helper()
+
+	cfg := newTestConfig(t)
+	cfg.Settings.Download.Retries = 3
+	cfg.Settings.Download.RetryBackoff = time.Millisecond
+	cfg.Settings.Download.MaxRetryBackoff = 5 * time.Millisecond
+
+	installer := NewInstaller(cfg)
+	installer.Quiet = true
+	return installer
+}
+
+func TestDownloadFileRetriesServerErrors(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, "kubectl binary")
+	}))
+	defer server.Close()
+
+	installer := newTestDownloader(t)
+	dest := filepath.Join(installer.config.KuveDir, "kubectl")
+
+	if err := installer.downloadFile(context.Background(), server.URL, dest, "test"); err != nil {
+		t.Fatalf("downloadFile() error = %v", err)
+	}
+
+	data, _ := os.ReadFile(dest)
+	if string(data) != "kubectl binary" {
+		t.Errorf("Downloaded content = %q", data)
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 requests, got %d", requests)
+	}
+	if _, err := os.Stat(dest + ".part"); !os.IsNotExist(err) {
+		t.Errorf("Expected partial file to be removed")
+	}
+}
+
+func TestDownloadFileDoesNotRetryNotFound(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	installer := newTestDownloader(t)
+	dest := filepath.Join(installer.config.KuveDir, "kubectl")
+
+	err := installer.downloadFile(context.Background(), server.URL, dest, "test")
+	if err == nil || !strings.Contains(err.Error(), "HTTP 404") {
+		t.Fatalf("downloadFile() error = %v, want HTTP 404", err)
+	}
+	if !errors.Is(err, ErrNotFound) || errors.Is(err, ErrNetwork) {
+		t.Errorf("downloadFile() error = %v, want ErrNotFound", err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected 1 request, got %d", requests)
+	}
+}
+
+func TestDownloadFileNetworkFailure(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	installer := newTestDownloader(t)
+	dest := filepath.Join(installer.config.KuveDir, "kubectl")
+
+	err := installer.downloadFile(context.Background(), server.URL, dest, "test")
+	if !errors.Is(err, ErrNetwork) {
+		t.Fatalf("downloadFile() error = %v, want ErrNetwork", err)
+	}
+}
+
+func TestDownloadFileResumesWithRange(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 1000)
+	half := len(content) / 2
+
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rangeHeader := r.Header.Get("Range")
+		ranges = append(ranges, rangeHeader)
+
+		if rangeHeader == "" {
+			// Announce the full size but drop the connection half way
+			w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+			w.WriteHeader(http.StatusOK)
+			w.Write(content[:half])
+			hj, _ := w.(http.Hijacker)
+			conn, _, _ := hj.Hijack()
+			conn.Close()
+			return
+		}
+
+		var start int
+		fmt.Sscanf(rangeHeader, "bytes=%d-", &start)
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(content)-1, len(content)))
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)-start))
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(content[start:])
+	}))
+	defer server.Close()
+
+	installer := newTestDownloader(t)
+	dest := filepath.Join(installer.config.KuveDir, "kubectl")
+
+	if err := installer.downloadFile(context.Background(), server.URL, dest, "test"); err != nil {
+		t.Fatalf("downloadFile() error = %v", err)
+	}
+
+	data, _ := os.ReadFile(dest)
+	if !bytes.Equal(data, content) {
+		t.Errorf("Downloaded content differs from source (%d bytes, want %d)", len(data), len(content))
+	}
+	if len(ranges) != 2 || ranges[1] != fmt.Sprintf("bytes=%d-", half) {
+		t.Errorf("Unexpected range requests %q", ranges)
+	}
+}
+
+func TestDownloadFileRestartsWhenRangeIgnored(t *testing.T) {
+	installer := newTestDownloader(t)
+	dest := filepath.Join(installer.config.KuveDir, "kubectl")
+
+	// Leftover partial file from a previous attempt
+	os.WriteFile(dest+".part", []byte("stale"), 0644)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "kubectl binary")
+	}))
+	defer server.Close()
+
+	if err := installer.downloadFile(context.Background(), server.URL, dest, "test"); err != nil {
+		t.Fatalf("downloadFile() error = %v", err)
+	}
+
+	data, _ := os.ReadFile(dest)
+	if string(data) != "kubectl binary" {
+		t.Errorf("Downloaded content = %q", data)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	base := 100 * time.Millisecond
+	maxDelay := time.Second
+
+	tests := []struct {
+		attempt int
+		min     time.Duration
+		max     time.Duration
+	}{
+		{attempt: 1, min: 50 * time.Millisecond, max: 100 * time.Millisecond},
+		{attempt: 2, min: 100 * time.Millisecond, max: 200 * time.Millisecond},
+		{attempt: 3, min: 200 * time.Millisecond, max: 400 * time.Millisecond},
+		{attempt: 10, min: 500 * time.Millisecond, max: time.Second},
+	}
+
+	for _, tt := range tests {
+		got := retryDelay(tt.attempt, base, maxDelay)
+		if got < tt.min || got >= tt.max {
+			t.Errorf("retryDelay(%d) = %s, want in [%s, %s)", tt.attempt, got, tt.min, tt.max)
+		}
+	}
+
+	if got := retryDelay(1, 0, maxDelay); got != 0 {
+		t.Errorf("retryDelay() with no backoff = %s, want 0", got)
+	}
+}
+
+func TestDownloadFileStopsOnCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	installer := newTestDownloader(t)
+	installer.config.Settings.Download.RetryBackoff = time.Hour
+	installer.config.Settings.Download.MaxRetryBackoff = time.Hour
+	dest := filepath.Join(installer.config.KuveDir, "kubectl")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	err := installer.downloadFile(ctx, server.URL, dest, "test")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("downloadFile() error = %v, want context.Canceled", err)
+	}
+	if _, err := os.Stat(dest + ".part"); !os.IsNotExist(err) {
+		t.Errorf("Expected partial file to be removed")
+	}
+}
+
+func TestSelectChecksum(t *testing.T) {
+	list := "AAAA  kustomize_v5.4.1_linux_amd64.tar.gz\nbbbb *kustomize_v5.4.1_darwin_arm64.tar.gz\n"
+
+	tests := []struct {
+		name     string
+		data     string
+		fileName string
+		want     string
+		wantErr  bool
+	}{
+		{name: "bare checksum", data: "ABCD\n", want: "abcd"},
+		{name: "single entry for another file", data: "abcd  kubectl\n", fileName: "helm", want: "abcd"},
+		{name: "list entry", data: list, fileName: "kustomize_v5.4.1_linux_amd64.tar.gz", want: "aaaa"},
+		{name: "binary mode entry", data: list, fileName: "kustomize_v5.4.1_darwin_arm64.tar.gz", want: "bbbb"},
+		{name: "missing entry", data: list, fileName: "kustomize_v5.4.1_windows_amd64.tar.gz", wantErr: true},
+		{name: "empty file", data: "\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := selectChecksum(tt.data, tt.fileName)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("selectChecksum() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("selectChecksum() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
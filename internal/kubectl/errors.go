@@ -0,0 +1,60 @@
+package kubectl
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors reported by the installer, to be tested with errors.Is
+var (
+	// ErrNotInstalled reports a version that is not installed
+	ErrNotInstalled = errors.New("version not installed")
+
+	// ErrAlreadyInstalled reports a version that is already installed. Only
+	// Import returns it since Install leaves installed versions untouched.
+	ErrAlreadyInstalled = errors.New("version already installed")
+
+	// ErrNotFound reports a release artifact the server does not have (HTTP 404)
+	ErrNotFound = errors.New("not found upstream")
+
+	// ErrNetwork reports a release server that could not be reached or failed to answer
+	ErrNetwork = errors.New("network failure")
+
+	// ErrChecksumMismatch reports a file that does not match its expected checksum
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+)
+
+// ChecksumError reports a file whose SHA-256 checksum differs from the expected one
+type ChecksumError struct {
+	Path     string
+	Expected string
+	Actual   string
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("checksum mismatch for %s: expected %s, got %s", e.Path, e.Expected, e.Actual)
+}
+
+// Is makes ChecksumError match ErrChecksumMismatch
+func (e *ChecksumError) Is(target error) bool {
+	return target == ErrChecksumMismatch
+}
+
+// kindError classifies an error as one of the errors above while keeping its message
+type kindError struct {
+	kind error
+	err  error
+}
+
+func (e *kindError) Error() string {
+	return e.err.Error()
+}
+
+func (e *kindError) Unwrap() []error {
+	return []error{e.kind, e.err}
+}
+
+// errorf formats an error classified as kind
+func errorf(kind error, format string, args ...any) error {
+	return &kindError{kind: kind, err: fmt.Errorf(format, args...)}
+}
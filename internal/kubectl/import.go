@@ -0,0 +1,187 @@
+package kubectl
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/germainlefebvre4/kuve/pkg/config"
+)
+
+// ImportOptions controls how a local kubectl is imported
+type ImportOptions struct {
+	// Version overrides or confirms the version detected from the binary
+	Version string
+
+	// SHA256 is the expected checksum of the imported file (binary or archive)
+	SHA256 string
+}
+
+// Import installs a kubectl binary or an official kubernetes-client tarball
+// from the local file system, for environments without network access.
+// The version is detected by running the binary unless opts.Version is set.
+// It returns the installed version.
+func (i *Installer) Import(ctx context.Context, srcPath string, opts ImportOptions) (string, error) {
+	if opts.SHA256 != "" {
+		if err := verifyFileSHA256(srcPath, opts.SHA256); err != nil {
+			return "", err
+		}
+	}
+
+	// Stage the binary next to the versions so that the final move is a rename
+	stagingDir, err := os.MkdirTemp(i.config.VersionsDir, ".import-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(stagingDir)
+
+	if err := os.Chmod(stagingDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create staging directory: %w", err)
+	}
+
+	stagedPath := filepath.Join(stagingDir, config.KubectlBinaryName)
+	if err := stageBinary(srcPath, stagedPath); err != nil {
+		return "", err
+	}
+
+	version, err := i.resolveImportVersion(ctx, stagedPath, opts.Version)
+	if err != nil {
+		return "", err
+	}
+
+	if err := recordMetadata(stagingDir, CurrentPlatform(), []string{config.KubectlBinaryName}); err != nil {
+		return "", err
+	}
+
+	versionDir := filepath.Join(i.config.VersionsDir, version)
+	if _, err := os.Stat(versionDir); err == nil {
+		return "", errorf(ErrAlreadyInstalled, "version %s is already installed", version)
+	}
+
+	if err := os.Rename(stagingDir, versionDir); err != nil {
+		return "", fmt.Errorf("failed to install version directory: %w", err)
+	}
+
+	fmt.Fprintf(i.Out, "Successfully imported kubectl %s from %s\n", version, srcPath)
+	return version, nil
+}
+
+// stageBinary copies or extracts the kubectl binary found at srcPath to destPath
+func stageBinary(srcPath, destPath string) error {
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", srcPath, err)
+	}
+	defer src.Close()
+
+	if isTarGz(srcPath) {
+		return extractBinary(src, config.KubectlBinaryName, destPath)
+	}
+
+	out, err := os.OpenFile(destPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, src); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to copy kubectl: %w", err)
+	}
+	return out.Close()
+}
+
+// resolveImportVersion detects the version of the staged binary and checks
+// it against the requested version. The requested version is used as is
+// when the binary cannot be run (e.g. built for another platform). Only full
+// versions are accepted since the version names the installed directory.
+func (i *Installer) resolveImportVersion(ctx context.Context, kubectlPath, requested string) (string, error) {
+	if requested != "" {
+		if !strings.HasPrefix(requested, "v") {
+			requested = "v" + requested
+		}
+		if err := config.ValidateVersion(requested); err != nil {
+			return "", err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, smokeTestTimeout)
+	defer cancel()
+
+	detected, err := clientVersion(ctx, kubectlPath)
+	if err != nil {
+		if requested == "" {
+			return "", fmt.Errorf("failed to detect kubectl version, use --version to set it: %w", err)
+		}
+		fmt.Fprintf(i.Out, "Warning: could not detect kubectl version (%v), using %s\n", err, requested)
+		return requested, nil
+	}
+
+	if err := config.ValidateVersion(detected); err != nil {
+		return "", fmt.Errorf("binary reports an unsupported version: %w", err)
+	}
+
+	if requested != "" && requested != detected {
+		return "", fmt.Errorf("version mismatch: binary reports %s but %s was requested", detected, requested)
+	}
+
+	return detected, nil
+}
+
+// clientVersion runs kubectl to read its client version
+func clientVersion(ctx context.Context, kubectlPath string) (string, error) {
+	cmd := exec.CommandContext(ctx, kubectlPath, "version", "--client", "--output=json")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+
+	var versionInfo struct {
+		ClientVersion struct {
+			GitVersion string `json:"gitVersion"`
+		} `json:"clientVersion"`
+	}
+
+	if err := json.Unmarshal(output, &versionInfo); err != nil {
+		return "", fmt.Errorf("failed to parse kubectl output: %w", err)
+	}
+
+	if versionInfo.ClientVersion.GitVersion == "" {
+		return "", fmt.Errorf("could not parse client version from kubectl output")
+	}
+
+	return versionInfo.ClientVersion.GitVersion, nil
+}
+
+// fileSHA256 returns the hex encoded SHA-256 checksum of a file
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// verifyFileSHA256 checks a file against an expected SHA-256 checksum
+func verifyFileSHA256(path, expected string) error {
+	actual, err := fileSHA256(path)
+	if err != nil {
+		return fmt.Errorf("failed to compute checksum: %w", err)
+	}
+
+	if !strings.EqualFold(actual, strings.TrimSpace(expected)) {
+		return &ChecksumError{Path: path, Expected: expected, Actual: actual}
+	}
+	return nil
+}
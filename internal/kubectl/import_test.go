@@ -0,0 +1,166 @@
+package kubectl
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/germainlefebvre4/kuve/pkg/config"
+)
+
+// fakeKubectl is a shell script answering 'kubectl version --client -o json'
+const fakeKubectl = `#!/bin/sh
+echo '{"clientVersion":{"gitVersion":"v1.28.3"}}'
+`
+
+func writeTarGz(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+
+	var ordered [][2]string
+	for name, content := range files {
+		ordered = append(ordered, [2]string{name, content})
+	}
+	writeOrderedTarGz(t, path, ordered)
+}
+
+// writeOrderedTarGz writes a gzipped tarball with entries in the given order
+func writeOrderedTarGz(t *testing.T, path string, files [][2]string) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, file := range files {
+		tw.WriteHeader(&tar.Header{Name: file[0], Mode: 0755, Size: int64(len(file[1])), Typeflag: tar.TypeReg})
+		tw.Write([]byte(file[1]))
+	}
+	tw.Close()
+	gz.Close()
+
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("Failed to write archive: %v", err)
+	}
+}
+
+func TestImportBinary(t *testing.T) {
+	cfg := newTestConfig(t)
+	installer := NewInstaller(cfg)
+
+	src := filepath.Join(cfg.KuveDir, "kubectl-download")
+	os.WriteFile(src, []byte(fakeKubectl), 0755)
+
+	version, err := installer.Import(context.Background(), src, ImportOptions{})
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if version != "v1.28.3" {
+		t.Errorf("Import() version = %s, want v1.28.3", version)
+	}
+
+	info, err := os.Stat(filepath.Join(cfg.VersionsDir, "v1.28.3", config.KubectlBinaryName))
+	if err != nil {
+		t.Fatalf("Imported kubectl not found: %v", err)
+	}
+	if info.Mode()&0111 == 0 {
+		t.Errorf("Imported kubectl is not executable")
+	}
+
+	// Importing the same version again fails
+	if _, err := installer.Import(context.Background(), src, ImportOptions{}); err == nil {
+		t.Errorf("Expected second Import() to fail")
+	}
+}
+
+func TestImportArchive(t *testing.T) {
+	cfg := newTestConfig(t)
+	installer := NewInstaller(cfg)
+
+	src := filepath.Join(cfg.KuveDir, "kubernetes-client-linux-amd64.tar.gz")
+	writeTarGz(t, src, map[string]string{
+		"kubernetes/client/bin/kubectl": fakeKubectl,
+	})
+
+	checksum, _ := fileSHA256(src)
+
+	// Wrong checksum is refused
+	_, err := installer.Import(context.Background(), src, ImportOptions{SHA256: strings.Repeat("0", 64)})
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("Import() error = %v, want checksum mismatch", err)
+	}
+
+	// Version mismatch is refused
+	_, err = installer.Import(context.Background(), src, ImportOptions{Version: "1.29.0"})
+	if err == nil || !strings.Contains(err.Error(), "version mismatch") {
+		t.Fatalf("Import() error = %v, want version mismatch", err)
+	}
+
+	version, err := installer.Import(context.Background(), src, ImportOptions{Version: "1.28.3", SHA256: checksum})
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if version != "v1.28.3" {
+		t.Errorf("Import() version = %s, want v1.28.3", version)
+	}
+
+	// No staging directory is left behind
+	entries, _ := os.ReadDir(cfg.VersionsDir)
+	if len(entries) != 1 {
+		t.Errorf("Expected only the imported version in %s, got %d entries", cfg.VersionsDir, len(entries))
+	}
+}
+
+func TestImportRejectsInvalidVersions(t *testing.T) {
+	cfg := newTestConfig(t)
+	installer := NewInstaller(cfg)
+	installer.Out = io.Discard
+
+	// A binary that cannot run falls back to the requested version
+	src := filepath.Join(cfg.KuveDir, "kubectl-other-platform")
+	os.WriteFile(src, []byte("not a binary"), 0755)
+	for _, requested := range []string{"v1/../../x", "1.30", "1.30.0-dirty"} {
+		if _, err := installer.Import(context.Background(), src, ImportOptions{Version: requested}); err == nil {
+			t.Errorf("Import() with version %q succeeded, want an invalid version error", requested)
+		}
+	}
+
+	// The version reported by the binary is checked as well
+	dirty := filepath.Join(cfg.KuveDir, "kubectl-dirty")
+	os.WriteFile(dirty, []byte(strings.Replace(fakeKubectl, "v1.28.3", "v1.28.3-dirty", 1)), 0755)
+	if _, err := installer.Import(context.Background(), dirty, ImportOptions{}); err == nil {
+		t.Errorf("Import() of a v1.28.3-dirty binary succeeded, want an invalid version error")
+	}
+
+	entries, _ := os.ReadDir(cfg.VersionsDir)
+	if len(entries) != 0 {
+		t.Errorf("Expected nothing installed in %s, got %d entries", cfg.VersionsDir, len(entries))
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(cfg.VersionsDir), "x")); err == nil {
+		t.Errorf("Import() wrote outside the versions directory")
+	}
+}
+
+func TestExtractBinaryRejectsTraversal(t *testing.T) {
+	tmpDir := newTestConfig(t).KuveDir
+
+	src := filepath.Join(tmpDir, "evil.tar.gz")
+	writeTarGz(t, src, map[string]string{
+		"../../kubectl": "evil",
+	})
+
+	f, err := os.Open(src)
+	if err != nil {
+		t.Fatalf("Failed to open archive: %v", err)
+	}
+	defer f.Close()
+
+	err = extractBinary(f, config.KubectlBinaryName, filepath.Join(tmpDir, "kubectl"))
+	if err == nil || !strings.Contains(err.Error(), "unsafe path") {
+		t.Errorf("extractBinary() error = %v, want unsafe path", err)
+	}
+}
@@ -1,32 +1,109 @@
 package kubectl
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
-	"runtime"
+	"slices"
+	"strings"
+	"sync"
 
+	"github.com/germainlefebvre4/kuve/internal/httpclient"
 	"github.com/germainlefebvre4/kuve/pkg/config"
 )
 
+const (
+	// DefaultConcurrency is the default number of parallel downloads
+	DefaultConcurrency = 4
+
+	// DefaultReleaseURL is the base URL of the official Kubernetes releases
+	DefaultReleaseURL = "https://dl.k8s.io/release"
+
+	// downloadURLTemplate is the URL of a binary for a base URL, version, OS, architecture and file name
+	downloadURLTemplate = "%s/%s/bin/%s/%s/%s"
+)
+
 // Installer handles kubectl installation
 type Installer struct {
 	config *config.Config
+
+	// client is the shared HTTP client, clientErr reports an invalid HTTP configuration
+	client    *http.Client
+	clientErr error
+
+	// Quiet disables download progress reporting
+	Quiet bool
+
+	// Out receives the messages reporting what was done (os.Stdout by default)
+	Out io.Writer
+
+	// Platform is the platform of the installed binaries (the current one by default).
+	// Binaries for another platform are installed in a platform-qualified store.
+	Platform Platform
+
+	// TargetDir overrides the directory versions are installed into
+	TargetDir string
+
+	// With lists the companion binaries (see config.CompanionBinaryNames)
+	// installed next to kubectl
+	With []string
+
+	// Force downloads the binaries of versions that are already installed again
+	Force bool
+
+	// VerifySignature requires a valid cosign signature of the downloaded
+	// kubectl artifacts (see config.SignatureSettings)
+	VerifySignature bool
+
+	// interactive enables the progress bar instead of periodic progress lines
+	interactive bool
 }
 
 // NewInstaller creates a new kubectl installer
 func NewInstaller(cfg *config.Config) *Installer {
+	client, err := httpclient.New(cfg.Settings.HTTP, cfg.Settings.Download.Mirrors)
 	return &Installer{
-		config: cfg,
+		config:          cfg,
+		client:          client,
+		clientErr:       err,
+		Out:             os.Stdout,
+		Platform:        CurrentPlatform(),
+		VerifySignature: cfg.Settings.Signature.Verify,
+		interactive:     isTerminal(os.Stderr),
 	}
 }
 
-// Install downloads and installs a specific kubectl version
-func (i *Installer) Install(version string) error {
+// Install downloads and installs a specific version of the managed tool
+// together with the companion binaries listed in With (kubectl only). Binaries already present in the
+// version directory are kept, so companions can be added to an existing install,
+// and installing a complete version again succeeds without downloading anything.
+// With Force, the binaries of an installed version are downloaded again and
+// replace the installed ones only once all of them were downloaded.
+// A cancelled context aborts the download and removes the partial install.
+func (i *Installer) Install(ctx context.Context, version string) error {
+	_, err := i.install(ctx, version)
+	return err
+}
+
+// install installs a version, reporting whether it was already installed and left untouched
+func (i *Installer) install(ctx context.Context, version string) (bool, error) {
 	if version == "" {
-		return fmt.Errorf("version cannot be empty")
+		return false, fmt.Errorf("version cannot be empty")
+	}
+
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	tool := i.config.Tool()
+	if len(i.With) > 0 && !tool.IsKubectl() {
+		return false, fmt.Errorf("companion binaries are only supported for kubectl")
+	}
+	if err := ValidateCompanions(i.With); err != nil {
+		return false, err
 	}
 
 	// Normalize version (ensure it starts with 'v')
@@ -34,42 +111,294 @@ func (i *Installer) Install(version string) error {
 		version = "v" + version
 	}
 
-	versionDir := filepath.Join(i.config.VersionsDir, version)
-	kubectlPath := filepath.Join(versionDir, config.KubectlBinaryName)
+	versionDir := filepath.Join(i.versionsDir(), version)
+
+	if i.Force {
+		if _, err := os.Stat(versionDir); err == nil {
+			return false, i.reinstall(ctx, version, versionDir)
+		}
+	}
+
+	// Collect the binaries that are not installed yet
+	var missing []string
+	for _, name := range append([]string{tool.BinaryName()}, i.With...) {
+		if _, err := os.Stat(filepath.Join(versionDir, i.Platform.BinaryName(name))); err != nil {
+			missing = append(missing, name)
+		}
+	}
 
 	// Check if already installed
-	if _, err := os.Stat(kubectlPath); err == nil {
-		return fmt.Errorf("version %s is already installed", version)
+	if len(missing) == 0 {
+		fmt.Fprintf(i.Out, "%s %s is already installed\n", tool.Name, version)
+		return true, nil
 	}
 
 	// Create version directory
+	_, statErr := os.Stat(versionDir)
+	createdDir := os.IsNotExist(statErr)
 	if err := os.MkdirAll(versionDir, 0755); err != nil {
-		return fmt.Errorf("failed to create version directory: %w", err)
+		return false, fmt.Errorf("failed to create version directory: %w", err)
+	}
+
+	// Cleanup on failure: drop the whole directory if this install created it,
+	// otherwise only the binaries downloaded so far
+	var downloaded []string
+	cleanup := func() {
+		if createdDir {
+			os.RemoveAll(versionDir)
+			return
+		}
+		for _, path := range downloaded {
+			os.Remove(path)
+		}
+	}
+
+	for _, name := range missing {
+		binaryPath, err := i.downloadInto(ctx, versionDir, name, version)
+		if err != nil {
+			cleanup()
+			return false, err
+		}
+		downloaded = append(downloaded, binaryPath)
+	}
+
+	var binaryNames []string
+	for _, name := range missing {
+		binaryNames = append(binaryNames, i.Platform.BinaryName(name))
+	}
+	if err := recordMetadata(versionDir, i.Platform, binaryNames); err != nil {
+		cleanup()
+		return false, err
+	}
+
+	what := tool.Name + " " + version
+	if missing[0] != tool.BinaryName() {
+		what = strings.Join(missing, ", ") + " for " + tool.Name + " " + version
+	} else if len(missing) > 1 {
+		what += " with " + strings.Join(missing[1:], ", ")
+	}
+	i.reportInstalled("installed", what, versionDir)
+	return false, nil
+}
+
+// reinstall downloads all binaries of an installed version (the tool, the
+// companions already installed and those listed in With) into a staging
+// directory, then moves each of them over the installed one. Each binary is
+// replaced atomically, and a failed download leaves the version untouched.
+func (i *Installer) reinstall(ctx context.Context, version, versionDir string) error {
+	tool := i.config.Tool()
+	names := append([]string{tool.BinaryName()}, i.With...)
+	if tool.IsKubectl() {
+		for _, name := range config.CompanionBinaryNames {
+			if _, err := os.Stat(filepath.Join(versionDir, i.Platform.BinaryName(name))); err == nil && !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+
+	// Stage next to the version so that the final moves are renames
+	stagingDir, err := os.MkdirTemp(filepath.Dir(versionDir), ".reinstall-*")
+	if err != nil {
+		return fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(stagingDir)
+
+	var binaryNames []string
+	for _, name := range names {
+		if _, err := i.downloadInto(ctx, stagingDir, name, version); err != nil {
+			return err
+		}
+		binaryNames = append(binaryNames, i.Platform.BinaryName(name))
+	}
+
+	// Start the metadata from the installed one, so that binaries
+	// not downloaded again keep their recorded checksums
+	if data, err := os.ReadFile(filepath.Join(versionDir, config.MetadataFileName)); err == nil {
+		if err := os.WriteFile(filepath.Join(stagingDir, config.MetadataFileName), data, 0644); err != nil {
+			return fmt.Errorf("failed to write install metadata: %w", err)
+		}
+	}
+	if err := recordMetadata(stagingDir, i.Platform, binaryNames); err != nil {
+		return err
+	}
+
+	for _, name := range append(binaryNames, config.MetadataFileName) {
+		if err := os.Rename(filepath.Join(stagingDir, name), filepath.Join(versionDir, name)); err != nil {
+			return fmt.Errorf("failed to replace %s: %w", name, err)
+		}
+	}
+
+	what := tool.Name + " " + version
+	if len(names) > 1 {
+		what += " with " + strings.Join(names[1:], ", ")
 	}
+	i.reportInstalled("reinstalled", what, versionDir)
+	return nil
+}
 
-	// Build download URL
-	downloadURL := fmt.Sprintf("https://dl.k8s.io/release/%s/bin/%s/%s/kubectl",
-		version, runtime.GOOS, runtime.GOARCH)
+// downloadInto downloads a binary of a version into dir and makes it executable
+func (i *Installer) downloadInto(ctx context.Context, dir, name, version string) (string, error) {
+	binaryPath := filepath.Join(dir, i.Platform.BinaryName(name))
 
-	// Download kubectl binary
-	fmt.Printf("Downloading kubectl %s for %s/%s...\n", version, runtime.GOOS, runtime.GOARCH)
-	if err := i.downloadFile(downloadURL, kubectlPath); err != nil {
-		os.RemoveAll(versionDir) // Cleanup on failure
-		return fmt.Errorf("failed to download kubectl: %w", err)
+	// Download binary
+	fmt.Fprintf(i.Out, "Downloading %s %s for %s...\n", name, version, i.Platform)
+	if err := i.DownloadBinary(ctx, name, version, i.Platform, binaryPath); err != nil {
+		return "", fmt.Errorf("failed to download %s: %w", name, err)
 	}
 
 	// Make binary executable
-	if err := os.Chmod(kubectlPath, 0755); err != nil {
-		os.RemoveAll(versionDir) // Cleanup on failure
-		return fmt.Errorf("failed to make kubectl executable: %w", err)
+	if err := os.Chmod(binaryPath, 0755); err != nil {
+		return "", fmt.Errorf("failed to make %s executable: %w", name, err)
+	}
+	return binaryPath, nil
+}
+
+// reportInstalled prints the success message of an install
+func (i *Installer) reportInstalled(action, what, versionDir string) {
+	if i.Platform != CurrentPlatform() || i.TargetDir != "" {
+		fmt.Fprintf(i.Out, "Successfully %s %s for %s to %s\n", action, what, i.Platform, versionDir)
+		return
 	}
+	fmt.Fprintf(i.Out, "Successfully %s %s\n", action, what)
+}
 
-	fmt.Printf("Successfully installed kubectl %s\n", version)
+// ValidateCompanions checks that the given names are supported companion binaries
+func ValidateCompanions(names []string) error {
+	for _, name := range names {
+		if !slices.Contains(config.CompanionBinaryNames, name) {
+			return fmt.Errorf("unsupported companion binary %q, supported: %s",
+				name, strings.Join(config.CompanionBinaryNames, ", "))
+		}
+	}
 	return nil
 }
 
-// Uninstall removes a specific kubectl version
-func (i *Installer) Uninstall(version string) error {
+// versionsDir returns the directory versions are installed into
+func (i *Installer) versionsDir() string {
+	if i.TargetDir != "" {
+		return i.TargetDir
+	}
+	if i.Platform != CurrentPlatform() {
+		return i.config.PlatformVersionsDir(i.Platform.OS, i.Platform.Arch)
+	}
+	return i.config.VersionsDir
+}
+
+// UpstreamChecksum fetches the published SHA-256 checksum of a kubectl binary
+// from the first mirror hosting bare binaries, or from dl.k8s.io
+func (i *Installer) UpstreamChecksum(ctx context.Context, version string, platform Platform) (string, error) {
+	baseURL := DefaultReleaseURL
+	for _, mirror := range i.mirrors() {
+		if mirror.Format == "" || mirror.Format == config.MirrorFormatBinary {
+			baseURL = strings.TrimSuffix(mirror.URL, "/")
+			break
+		}
+	}
+
+	checksumURL := fmt.Sprintf(downloadURLTemplate, baseURL, version, platform.OS, platform.Arch,
+		platform.BinaryName(config.KubectlBinaryName)) + ".sha256"
+	return i.fetchChecksum(ctx, checksumURL, "")
+}
+
+// BinaryChecksum returns the SHA-256 checksum of the binary of a version for
+// a platform. The published checksum is used when it covers the bare binary;
+// otherwise the binary is downloaded, verified and hashed.
+func (i *Installer) BinaryChecksum(ctx context.Context, version string, platform Platform) (string, error) {
+	tool := i.config.Tool()
+	if tool.IsKubectl() {
+		return i.UpstreamChecksum(ctx, version, platform)
+	}
+
+	if tool.Archive == "" && tool.ChecksumURL != "" {
+		data := tool.URLData(version, platform.OS, platform.Arch)
+		downloadURL, err := tool.ExpandURL(tool.URL, data)
+		if err != nil {
+			return "", err
+		}
+		checksumURL, err := tool.ExpandURL(tool.ChecksumURL, data)
+		if err != nil {
+			return "", err
+		}
+		return i.fetchChecksum(ctx, checksumURL, artifactFileName(downloadURL))
+	}
+
+	tmpDir, err := os.MkdirTemp("", "kuve-checksum-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create working directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	binaryPath := filepath.Join(tmpDir, platform.BinaryName(tool.BinaryName()))
+	if err := i.DownloadBinary(ctx, tool.BinaryName(), version, platform, binaryPath); err != nil {
+		return "", fmt.Errorf("failed to download %s %s for %s: %w", tool.Name, version, platform, err)
+	}
+	return fileSHA256(binaryPath)
+}
+
+// VerifyInstalled checks the installed binary of a version against an expected SHA-256 checksum
+func (i *Installer) VerifyInstalled(version, expected string) error {
+	binaryPath := filepath.Join(i.versionsDir(), version, i.Platform.BinaryName(i.config.Tool().BinaryName()))
+	if _, err := os.Stat(binaryPath); os.IsNotExist(err) {
+		return errorf(ErrNotInstalled, "version %s is not installed", version)
+	}
+	return verifyFileSHA256(binaryPath, expected)
+}
+
+// InstallResult holds the outcome of installing a single version
+type InstallResult struct {
+	Version string
+	Err     error
+
+	// AlreadyInstalled reports a version that was installed before and left untouched
+	AlreadyInstalled bool
+}
+
+// InstallMany installs several kubectl versions using at most workers
+// parallel downloads. A failing version does not abort the others; the
+// results are returned in the order of the requested versions.
+func (i *Installer) InstallMany(ctx context.Context, versions []string, workers int) []InstallResult {
+	if workers < 1 {
+		workers = 1
+	}
+
+	// Concurrent progress bars would overwrite each other on a terminal,
+	// so parallel downloads report progress as periodic lines instead
+	worker := *i
+	if workers > 1 && len(versions) > 1 {
+		worker.interactive = false
+	}
+
+	results := make([]InstallResult, len(versions))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < len(versions); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				alreadyInstalled, err := worker.install(ctx, versions[idx])
+				results[idx] = InstallResult{
+					Version:          versions[idx],
+					Err:              err,
+					AlreadyInstalled: alreadyInstalled,
+				}
+			}
+		}()
+	}
+
+	for idx := range versions {
+		jobs <- idx
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// Uninstall removes a specific version of the managed tool.
+// Pinned versions are only removed when force is set.
+func (i *Installer) Uninstall(version string, force bool) error {
 	if version == "" {
 		return fmt.Errorf("version cannot be empty")
 	}
@@ -83,7 +412,7 @@ func (i *Installer) Uninstall(version string) error {
 
 	// Check if version is installed
 	if _, err := os.Stat(versionDir); os.IsNotExist(err) {
-		return fmt.Errorf("version %s is not installed", version)
+		return errorf(ErrNotInstalled, "version %s is not installed", version)
 	}
 
 	// Check if this is the current version
@@ -94,16 +423,77 @@ func (i *Installer) Uninstall(version string) error {
 		}
 	}
 
+	// Check if this version is pinned
+	if !force && i.config.IsPinned(version) {
+		return fmt.Errorf("cannot uninstall %s as it is pinned. Run 'kuve unpin %s' or use --force", version, version)
+	}
+
 	// Remove version directory
 	if err := os.RemoveAll(versionDir); err != nil {
 		return fmt.Errorf("failed to remove version directory: %w", err)
 	}
 
-	fmt.Printf("Successfully uninstalled kubectl %s\n", version)
+	fmt.Fprintf(i.Out, "Successfully uninstalled %s %s\n", i.config.Tool().Name, version)
+	return nil
+}
+
+// Pin protects an installed version from removal
+func (i *Installer) Pin(version string) error {
+	if version == "" {
+		return fmt.Errorf("version cannot be empty")
+	}
+
+	// Normalize version
+	if version[0] != 'v' {
+		version = "v" + version
+	}
+
+	versionDir := filepath.Join(i.config.VersionsDir, version)
+
+	// Check if version is installed
+	if _, err := os.Stat(versionDir); os.IsNotExist(err) {
+		return errorf(ErrNotInstalled, "version %s is not installed", version)
+	}
+
+	if i.config.IsPinned(version) {
+		fmt.Fprintf(i.Out, "%s %s is already pinned\n", i.config.Tool().Name, version)
+		return nil
+	}
+
+	pinFile := filepath.Join(versionDir, config.PinFileName)
+	if err := os.WriteFile(pinFile, []byte{}, 0644); err != nil {
+		return fmt.Errorf("failed to pin version: %w", err)
+	}
+
+	fmt.Fprintf(i.Out, "Pinned %s %s\n", i.config.Tool().Name, version)
+	return nil
+}
+
+// Unpin removes the removal protection from a version
+func (i *Installer) Unpin(version string) error {
+	if version == "" {
+		return fmt.Errorf("version cannot be empty")
+	}
+
+	// Normalize version
+	if version[0] != 'v' {
+		version = "v" + version
+	}
+
+	if !i.config.IsPinned(version) {
+		return fmt.Errorf("version %s is not pinned", version)
+	}
+
+	pinFile := filepath.Join(i.config.VersionsDir, version, config.PinFileName)
+	if err := os.Remove(pinFile); err != nil {
+		return fmt.Errorf("failed to unpin version: %w", err)
+	}
+
+	fmt.Fprintf(i.Out, "Unpinned %s %s\n", i.config.Tool().Name, version)
 	return nil
 }
 
-// Switch changes the active kubectl version
+// Switch changes the active version of the managed tool
 func (i *Installer) Switch(version string) error {
 	if version == "" {
 		return fmt.Errorf("version cannot be empty")
@@ -114,12 +504,13 @@ func (i *Installer) Switch(version string) error {
 		version = "v" + version
 	}
 
+	tool := i.config.Tool()
 	versionDir := filepath.Join(i.config.VersionsDir, version)
-	kubectlPath := filepath.Join(versionDir, config.KubectlBinaryName)
+	binaryPath := filepath.Join(versionDir, tool.BinaryName())
 
 	// Check if version is installed
-	if _, err := os.Stat(kubectlPath); os.IsNotExist(err) {
-		return fmt.Errorf("version %s is not installed. Run 'kuve install %s' first", version, version)
+	if _, err := os.Stat(binaryPath); os.IsNotExist(err) {
+		return errorf(ErrNotInstalled, "version %s is not installed. Run 'kuve install %s' first", version, version)
 	}
 
 	// Remove existing symlink if it exists
@@ -131,33 +522,48 @@ func (i *Installer) Switch(version string) error {
 	}
 
 	// Create new symlink
-	if err := os.Symlink(kubectlPath, currentSymlink); err != nil {
+	if err := os.Symlink(binaryPath, currentSymlink); err != nil {
 		return fmt.Errorf("failed to create symlink: %w", err)
 	}
 
-	fmt.Printf("Switched to kubectl %s\n", version)
-	fmt.Printf("Note: Make sure %s is in your PATH\n", i.config.BinDir)
-	return nil
-}
-
-// downloadFile downloads a file from a URL and saves it to destPath
-func (i *Installer) downloadFile(url, destPath string) error {
-	resp, err := http.Get(url)
-	if err != nil {
-		return err
+	if !tool.IsKubectl() {
+		fmt.Fprintf(i.Out, "Switched to %s %s\n", tool.Name, version)
+		fmt.Fprintf(i.Out, "Note: Make sure %s is in your PATH\n", i.config.BinDir)
+		return nil
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to download: HTTP %d", resp.StatusCode)
+	// Expose the companion binaries of the version, dropping the links of
+	// the previous version for companions this version does not provide
+	var linked []string
+	for _, name := range config.CompanionBinaryNames {
+		linkPath := filepath.Join(i.config.BinDir, name)
+		if info, err := os.Lstat(linkPath); err == nil {
+			if info.Mode()&os.ModeSymlink == 0 {
+				// Not managed by kuve, leave it alone
+				continue
+			}
+			if err := os.Remove(linkPath); err != nil {
+				return fmt.Errorf("failed to remove existing symlink: %w", err)
+			}
+		}
+
+		binaryPath := filepath.Join(versionDir, name)
+		if _, err := os.Stat(binaryPath); err != nil {
+			continue
+		}
+		if err := os.Symlink(binaryPath, linkPath); err != nil {
+			return fmt.Errorf("failed to create symlink: %w", err)
+		}
+		linked = append(linked, name)
 	}
 
-	out, err := os.Create(destPath)
-	if err != nil {
-		return err
+	if len(linked) > 0 {
+		fmt.Fprintf(i.Out, "Switched to kubectl %s (with %s)\n", version, strings.Join(linked, ", "))
+		fmt.Fprintf(i.Out, "Note: Make sure %s is in your PATH\n", i.config.BinDir)
+		return nil
 	}
-	defer out.Close()
 
-	_, err = io.Copy(out, resp.Body)
-	return err
+	fmt.Fprintf(i.Out, "Switched to kubectl %s\n", version)
+	fmt.Fprintf(i.Out, "Note: Make sure %s is in your PATH\n", i.config.BinDir)
+	return nil
 }
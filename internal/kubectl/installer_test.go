@@ -0,0 +1,591 @@
+package kubectl
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/germainlefebvre4/kuve/pkg/config"
+)
+
+func newTestConfig(t *testing.T) *config.Config {
+	t.Helper()
+
+	// Create a temporary directory
+	tmpDir, err := os.MkdirTemp("", "kuve-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(tmpDir) })
+
+	cfg := &config.Config{
+		HomeDir:        tmpDir,
+		KuveDir:        tmpDir,
+		BinDir:         filepath.Join(tmpDir, "bin"),
+		VersionsDir:    filepath.Join(tmpDir, "versions"),
+		CurrentSymlink: filepath.Join(tmpDir, "bin", "kubectl"),
+	}
+	if err := cfg.EnsureDirectories(); err != nil {
+		t.Fatalf("Failed to create directories: %v", err)
+	}
+
+	return cfg
+}
+
+func installFakeVersion(t *testing.T, cfg *config.Config, version string) {
+	t.Helper()
+
+	vDir := filepath.Join(cfg.VersionsDir, version)
+	if err := os.MkdirAll(vDir, 0755); err != nil {
+		t.Fatalf("Failed to create version dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(vDir, config.KubectlBinaryName), []byte("fake kubectl"), 0755); err != nil {
+		t.Fatalf("Failed to write fake kubectl: %v", err)
+	}
+}
+
+// fakeBinaryContent is the content served as a release binary of a version and platform
+func fakeBinaryContent(name, version, goos, goarch string) string {
+	return fmt.Sprintf("%s %s %s/%s", name, version, goos, goarch)
+}
+
+// fakeKubectlContent is the content served as the kubectl binary of a version and platform
+func fakeKubectlContent(version, goos, goarch string) string {
+	return fakeBinaryContent("kubectl", version, goos, goarch)
+}
+
+// newReleaseServer serves fake kubectl binaries, client tarballs and their
+// checksums with the same layout as dl.k8s.io
+func newReleaseServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	tarballRegex := regexp.MustCompile(`^/([^/]+)/kubernetes-client-([^-]+)-([^-]+)\.tar\.gz(\.sha256)?$`)
+	binaryRegex := regexp.MustCompile(`^/([^/]+)/bin/([^/]+)/([^/]+)/(kubectl|kubectl-convert|kubeadm)(\.exe)?(\.sha256)?$`)
+
+	serve := func(w http.ResponseWriter, content []byte, checksum bool) {
+		if checksum {
+			sum := sha256.Sum256(content)
+			fmt.Fprint(w, hex.EncodeToString(sum[:]))
+			return
+		}
+		w.Write(content)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m := binaryRegex.FindStringSubmatch(r.URL.Path); m != nil {
+			serve(w, []byte(fakeBinaryContent(m[4], m[1], m[2], m[3])), m[6] != "")
+			return
+		}
+
+		if m := tarballRegex.FindStringSubmatch(r.URL.Path); m != nil {
+			var buf bytes.Buffer
+			gz := gzip.NewWriter(&buf)
+			tw := tar.NewWriter(gz)
+			content := fakeKubectlContent(m[1], m[2], m[3])
+			tw.WriteHeader(&tar.Header{Name: "kubernetes/client/bin/", Mode: 0755, Typeflag: tar.TypeDir})
+			tw.WriteHeader(&tar.Header{Name: "kubernetes/client/bin/kubectl", Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg})
+			tw.Write([]byte(content))
+			tw.Close()
+			gz.Close()
+			serve(w, buf.Bytes(), m[4] != "")
+			return
+		}
+
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestUninstallPinnedVersion(t *testing.T) {
+	cfg := newTestConfig(t)
+	installer := NewInstaller(cfg)
+	installFakeVersion(t, cfg, "v1.28.0")
+
+	if err := installer.Pin("1.28.0"); err != nil {
+		t.Fatalf("Pin() error = %v", err)
+	}
+
+	if err := installer.Uninstall("v1.28.0", false); err == nil {
+		t.Errorf("Expected Uninstall() of a pinned version to fail")
+	}
+
+	if err := installer.Uninstall("v1.28.0", true); err != nil {
+		t.Errorf("Uninstall() with force error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(cfg.VersionsDir, "v1.28.0")); !os.IsNotExist(err) {
+		t.Errorf("Expected version directory to be removed")
+	}
+}
+
+func TestUnpin(t *testing.T) {
+	cfg := newTestConfig(t)
+	installer := NewInstaller(cfg)
+	installFakeVersion(t, cfg, "v1.28.0")
+
+	if err := installer.Unpin("v1.28.0"); err == nil {
+		t.Errorf("Expected Unpin() of an unpinned version to fail")
+	}
+
+	if err := installer.Pin("v1.28.0"); err != nil {
+		t.Fatalf("Pin() error = %v", err)
+	}
+	if err := installer.Unpin("v1.28.0"); err != nil {
+		t.Fatalf("Unpin() error = %v", err)
+	}
+
+	if err := installer.Uninstall("v1.28.0", false); err != nil {
+		t.Errorf("Uninstall() after unpin error = %v", err)
+	}
+}
+
+func TestPinNotInstalled(t *testing.T) {
+	cfg := newTestConfig(t)
+	installer := NewInstaller(cfg)
+
+	if err := installer.Pin("v1.28.0"); !errors.Is(err, ErrNotInstalled) {
+		t.Errorf("Pin() error = %v, want ErrNotInstalled", err)
+	}
+}
+
+func TestInstallManyKeepsOrderAndErrors(t *testing.T) {
+	cfg := newTestConfig(t)
+	installer := NewInstaller(cfg)
+	installFakeVersion(t, cfg, "v1.28.0")
+	installFakeVersion(t, cfg, "v1.29.0")
+
+	versions := []string{"v1.28.0", "", "v1.29.0"}
+	results := installer.InstallMany(context.Background(), versions, 2)
+
+	if len(results) != len(versions) {
+		t.Fatalf("Expected %d results, got %d", len(versions), len(results))
+	}
+
+	for idx, result := range results {
+		if result.Version != versions[idx] {
+			t.Errorf("results[%d].Version = %q, want %q", idx, result.Version, versions[idx])
+		}
+	}
+
+	// Installed versions succeed without network access, the empty one fails
+	for _, idx := range []int{0, 2} {
+		if results[idx].Err != nil || !results[idx].AlreadyInstalled {
+			t.Errorf("results[%d] = %+v, want already installed", idx, results[idx])
+		}
+	}
+	if results[1].Err == nil {
+		t.Errorf("results[1].Err = nil, want error")
+	}
+}
+
+func TestInstallCancelled(t *testing.T) {
+	cfg := newTestConfig(t)
+	installer := NewInstaller(cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := installer.Install(ctx, "v1.28.0"); err == nil {
+		t.Fatalf("Expected Install() with a cancelled context to fail")
+	}
+	if _, err := os.Stat(filepath.Join(cfg.VersionsDir, "v1.28.0")); !os.IsNotExist(err) {
+		t.Errorf("Expected no version directory after a cancelled install")
+	}
+}
+
+func TestInstallForOtherPlatform(t *testing.T) {
+	server := newReleaseServer(t)
+
+	cfg := newTestConfig(t)
+	cfg.PlatformsDir = filepath.Join(cfg.KuveDir, "platforms")
+	cfg.Settings.Download.Mirrors = []config.Mirror{{URL: server.URL}}
+
+	installer := NewInstaller(cfg)
+	installer.Quiet = true
+	installer.Platform = Platform{OS: "windows", Arch: "arm64"}
+
+	if err := installer.Install(context.Background(), "v1.28.3"); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	binaryPath := filepath.Join(cfg.PlatformsDir, "windows-arm64", "v1.28.3", "kubectl.exe")
+	data, err := os.ReadFile(binaryPath)
+	if err != nil {
+		t.Fatalf("Expected kubectl.exe in the platform store: %v", err)
+	}
+	if string(data) != fakeKubectlContent("v1.28.3", "windows", "arm64") {
+		t.Errorf("Installed the wrong binary: %q", data)
+	}
+
+	// The native store and the active version are untouched
+	if _, err := os.Stat(filepath.Join(cfg.VersionsDir, "v1.28.3")); !os.IsNotExist(err) {
+		t.Errorf("Foreign platform install must not populate the versions directory")
+	}
+	if _, err := os.Lstat(cfg.CurrentSymlink); !os.IsNotExist(err) {
+		t.Errorf("Foreign platform install must not change the active version")
+	}
+
+	// --dir installs into the given directory
+	installer.TargetDir = filepath.Join(cfg.KuveDir, "out")
+	installer.Platform = Platform{OS: "linux", Arch: "arm64"}
+	if err := installer.Install(context.Background(), "v1.28.3"); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.KuveDir, "out", "v1.28.3", "kubectl")); err != nil {
+		t.Errorf("Expected kubectl in the target directory: %v", err)
+	}
+}
+
+func TestInstallFromTarballMirror(t *testing.T) {
+	server := newReleaseServer(t)
+
+	cfg := newTestConfig(t)
+	cfg.Settings.Download.Mirrors = []config.Mirror{
+		{URL: server.URL + "/missing"},
+		{URL: server.URL + "/", Format: config.MirrorFormatTarball},
+	}
+	cfg.Settings.Download.Retries = 0
+
+	installer := NewInstaller(cfg)
+	installer.Quiet = true
+	installer.Platform = Platform{OS: "linux", Arch: "amd64"}
+	installer.TargetDir = cfg.VersionsDir
+
+	if err := installer.Install(context.Background(), "v1.28.3"); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	versionDir := filepath.Join(cfg.VersionsDir, "v1.28.3")
+	data, err := os.ReadFile(filepath.Join(versionDir, config.KubectlBinaryName))
+	if err != nil {
+		t.Fatalf("Installed kubectl not found: %v", err)
+	}
+	if string(data) != fakeKubectlContent("v1.28.3", "linux", "amd64") {
+		t.Errorf("Installed the wrong binary: %q", data)
+	}
+
+	// Only the binary and its install metadata are kept in the version directory
+	entries, _ := os.ReadDir(versionDir)
+	for _, entry := range entries {
+		if entry.Name() != config.KubectlBinaryName && entry.Name() != config.MetadataFileName {
+			t.Errorf("Unexpected %s left in %s", entry.Name(), versionDir)
+		}
+	}
+}
+
+func TestInstallRejectsChecksumMismatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ".sha256") {
+			fmt.Fprint(w, strings.Repeat("0", 64))
+			return
+		}
+		fmt.Fprint(w, "tampered kubectl")
+	}))
+	defer server.Close()
+
+	cfg := newTestConfig(t)
+	cfg.Settings.Download.Mirrors = []config.Mirror{{URL: server.URL}}
+
+	installer := NewInstaller(cfg)
+	installer.Quiet = true
+	installer.Platform = Platform{OS: "linux", Arch: "amd64"}
+	installer.TargetDir = cfg.VersionsDir
+
+	err := installer.Install(context.Background(), "v1.28.3")
+	if !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("Install() error = %v, want checksum mismatch", err)
+	}
+	var checksumErr *ChecksumError
+	if !errors.As(err, &checksumErr) || checksumErr.Expected != strings.Repeat("0", 64) {
+		t.Errorf("Install() error = %v, want a ChecksumError", err)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.VersionsDir, "v1.28.3")); !os.IsNotExist(err) {
+		t.Errorf("Expected no version directory after a checksum mismatch")
+	}
+}
+
+func TestInstallRequiresSignature(t *testing.T) {
+	server := newReleaseServer(t)
+
+	cfg := newTestConfig(t)
+	cfg.Settings.Download.Mirrors = []config.Mirror{{URL: server.URL}}
+	cfg.Settings.Signature = config.DefaultSettings().Signature
+
+	installer := NewInstaller(cfg)
+	installer.Quiet = true
+	installer.Platform = Platform{OS: "linux", Arch: "amd64"}
+	installer.TargetDir = cfg.VersionsDir
+	installer.VerifySignature = true
+
+	// The release server publishes checksums but no signatures
+	err := installer.Install(context.Background(), "v1.28.3")
+	if err == nil || !strings.Contains(err.Error(), "signature verification failed") {
+		t.Fatalf("Install() error = %v, want a signature failure", err)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.VersionsDir, "v1.28.3")); !os.IsNotExist(err) {
+		t.Errorf("Expected no version directory without a valid signature")
+	}
+}
+
+func TestInstallWithCompanions(t *testing.T) {
+	server := newReleaseServer(t)
+
+	cfg := newTestConfig(t)
+	cfg.Settings.Download.Mirrors = []config.Mirror{{URL: server.URL}}
+
+	installer := NewInstaller(cfg)
+	installer.Quiet = true
+	installer.Platform = Platform{OS: "linux", Arch: "amd64"}
+	installer.TargetDir = cfg.VersionsDir
+
+	if err := installer.Install(context.Background(), "v1.28.3"); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	// Companions are added to an existing install
+	installer.With = []string{config.KubectlConvertBinaryName, config.KubeadmBinaryName}
+	if err := installer.Install(context.Background(), "v1.28.3"); err != nil {
+		t.Fatalf("Install() with companions error = %v", err)
+	}
+
+	versionDir := filepath.Join(cfg.VersionsDir, "v1.28.3")
+	for _, name := range []string{config.KubectlBinaryName, config.KubectlConvertBinaryName, config.KubeadmBinaryName} {
+		data, err := os.ReadFile(filepath.Join(versionDir, name))
+		if err != nil {
+			t.Fatalf("%s not installed: %v", name, err)
+		}
+		if string(data) != fakeBinaryContent(name, "v1.28.3", "linux", "amd64") {
+			t.Errorf("Installed the wrong %s: %q", name, data)
+		}
+	}
+
+	// Installing again is a no-op
+	results := installer.InstallMany(context.Background(), []string{"v1.28.3"}, 1)
+	if results[0].Err != nil || !results[0].AlreadyInstalled {
+		t.Errorf("InstallMany() = %+v, want an already installed success", results[0])
+	}
+
+	installer.With = []string{"kubelet"}
+	if err := installer.Install(context.Background(), "v1.28.3"); err == nil {
+		t.Errorf("Expected Install() to reject an unsupported companion")
+	}
+}
+
+func TestInstallForceReplacesBinaries(t *testing.T) {
+	server := newReleaseServer(t)
+
+	cfg := newTestConfig(t)
+	cfg.Settings.Download.Mirrors = []config.Mirror{{URL: server.URL}}
+
+	installer := NewInstaller(cfg)
+	installer.Quiet = true
+	installer.Platform = Platform{OS: "linux", Arch: "amd64"}
+	installer.TargetDir = cfg.VersionsDir
+	installer.With = []string{config.KubeadmBinaryName}
+
+	if err := installer.Install(context.Background(), "v1.28.3"); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if err := installer.Pin("v1.28.3"); err != nil {
+		t.Fatalf("Pin() error = %v", err)
+	}
+
+	versionDir := filepath.Join(cfg.VersionsDir, "v1.28.3")
+	for _, name := range []string{config.KubectlBinaryName, config.KubeadmBinaryName} {
+		os.WriteFile(filepath.Join(versionDir, name), []byte("corrupted"), 0755)
+	}
+
+	// Without --with, the installed companions are downloaded again as well
+	installer.With = nil
+	installer.Force = true
+	if err := installer.Install(context.Background(), "v1.28.3"); err != nil {
+		t.Fatalf("Install() with Force error = %v", err)
+	}
+
+	for _, name := range []string{config.KubectlBinaryName, config.KubeadmBinaryName} {
+		data, _ := os.ReadFile(filepath.Join(versionDir, name))
+		if string(data) != fakeBinaryContent(name, "v1.28.3", "linux", "amd64") {
+			t.Errorf("%s not replaced: %q", name, data)
+		}
+	}
+	if !cfg.IsPinned("v1.28.3") {
+		t.Errorf("Expected the pin to survive a reinstall")
+	}
+	sum := sha256.Sum256([]byte(fakeKubectlContent("v1.28.3", "linux", "amd64")))
+	if err := installer.VerifyInstalled("v1.28.3", hex.EncodeToString(sum[:])); err != nil {
+		t.Errorf("VerifyInstalled() error = %v", err)
+	}
+
+	// A failed download leaves the installed version untouched
+	server.Close()
+	os.WriteFile(filepath.Join(versionDir, config.KubectlBinaryName), []byte("old"), 0755)
+	if err := installer.Install(context.Background(), "v1.28.3"); err == nil {
+		t.Fatalf("Expected Install() with Force to fail without a server")
+	}
+	if data, _ := os.ReadFile(filepath.Join(versionDir, config.KubectlBinaryName)); string(data) != "old" {
+		t.Errorf("Expected the installed kubectl to be kept, got %q", data)
+	}
+	entries, _ := os.ReadDir(cfg.VersionsDir)
+	if len(entries) != 1 {
+		t.Errorf("Expected no staging directory left, got %d entries", len(entries))
+	}
+}
+
+func TestSwitchLinksCompanions(t *testing.T) {
+	cfg := newTestConfig(t)
+	installer := NewInstaller(cfg)
+
+	installFakeVersion(t, cfg, "v1.28.0")
+	installFakeVersion(t, cfg, "v1.29.0")
+	os.WriteFile(filepath.Join(cfg.VersionsDir, "v1.28.0", config.KubeadmBinaryName), []byte("fake kubeadm"), 0755)
+
+	if err := installer.Switch("v1.28.0"); err != nil {
+		t.Fatalf("Switch() error = %v", err)
+	}
+
+	kubeadmLink := filepath.Join(cfg.BinDir, config.KubeadmBinaryName)
+	target, err := os.Readlink(kubeadmLink)
+	if err != nil {
+		t.Fatalf("Expected kubeadm symlink: %v", err)
+	}
+	if target != filepath.Join(cfg.VersionsDir, "v1.28.0", config.KubeadmBinaryName) {
+		t.Errorf("kubeadm symlink points to %s", target)
+	}
+
+	// Switching to a version without kubeadm drops the stale link
+	if err := installer.Switch("v1.29.0"); err != nil {
+		t.Fatalf("Switch() error = %v", err)
+	}
+	if _, err := os.Lstat(kubeadmLink); !os.IsNotExist(err) {
+		t.Errorf("Expected kubeadm symlink to be removed")
+	}
+}
+
+func TestInstallAndSwitchTool(t *testing.T) {
+	archiveDir := t.TempDir()
+	archivePath := filepath.Join(archiveDir, "helm.tar.gz")
+	writeTarGz(t, archivePath, map[string]string{"linux-amd64/helm": "fake helm v3.14.0"})
+	archive, err := os.ReadFile(archivePath)
+	if err != nil {
+		t.Fatalf("Failed to read archive: %v", err)
+	}
+	sum := sha256.Sum256(archive)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/helm-v3.14.0-linux-amd64.tar.gz":
+			w.Write(archive)
+		case "/checksums.txt":
+			// A list covering several artifacts
+			fmt.Fprintf(w, "%s  helm-v3.14.0-darwin-arm64.tar.gz\n", strings.Repeat("0", 64))
+			fmt.Fprintf(w, "%s  helm-v3.14.0-linux-amd64.tar.gz\n", hex.EncodeToString(sum[:]))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	base := newTestConfig(t)
+	base.Settings.ToolDefinitions = map[string]config.Tool{
+		"helm": {
+			VersionFile: ".helm-version",
+			URL:         server.URL + "/helm-{{.Version}}-{{.OS}}-{{.Arch}}.tar.gz",
+			Archive:     config.ArchiveFormatTarGz,
+			ChecksumURL: server.URL + "/checksums.txt",
+		},
+	}
+	cfg, err := base.ForTool("helm")
+	if err != nil {
+		t.Fatalf("ForTool() error = %v", err)
+	}
+	if err := cfg.EnsureDirectories(); err != nil {
+		t.Fatalf("Failed to create directories: %v", err)
+	}
+
+	installer := NewInstaller(cfg)
+	installer.Quiet = true
+	installer.Platform = Platform{OS: "linux", Arch: "amd64"}
+	installer.TargetDir = cfg.VersionsDir
+
+	if err := installer.Install(context.Background(), "3.14.0"); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	binaryPath := filepath.Join(base.KuveDir, "tools", "helm", "versions", "v3.14.0", "helm")
+	data, err := os.ReadFile(binaryPath)
+	if err != nil {
+		t.Fatalf("helm not installed: %v", err)
+	}
+	if string(data) != "fake helm v3.14.0" {
+		t.Errorf("Installed the wrong helm: %q", data)
+	}
+	if _, err := os.Stat(binaryPath + ".tar.gz"); !os.IsNotExist(err) {
+		t.Errorf("Expected the downloaded archive to be removed")
+	}
+
+	if err := installer.Switch("v3.14.0"); err != nil {
+		t.Fatalf("Switch() error = %v", err)
+	}
+	target, err := os.Readlink(filepath.Join(base.BinDir, "helm"))
+	if err != nil || target != binaryPath {
+		t.Errorf("helm symlink = %q, %v; want %s", target, err, binaryPath)
+	}
+
+	// kubectl is left untouched
+	if _, err := os.Lstat(base.CurrentSymlink); !os.IsNotExist(err) {
+		t.Errorf("Expected no kubectl symlink")
+	}
+
+	installer.With = []string{config.KubeadmBinaryName}
+	if err := installer.Install(context.Background(), "3.15.0"); err == nil {
+		t.Errorf("Expected companions to be rejected for helm")
+	}
+}
+
+func TestBinaryChecksumAndVerifyInstalled(t *testing.T) {
+	server := newReleaseServer(t)
+
+	cfg := newTestConfig(t)
+	cfg.Settings.Download.Mirrors = []config.Mirror{{URL: server.URL}}
+
+	installer := NewInstaller(cfg)
+	installer.Quiet = true
+	installer.Platform = Platform{OS: "linux", Arch: "amd64"}
+	installer.TargetDir = cfg.VersionsDir
+
+	checksum, err := installer.BinaryChecksum(context.Background(), "v1.28.3", installer.Platform)
+	if err != nil {
+		t.Fatalf("BinaryChecksum() error = %v", err)
+	}
+	sum := sha256.Sum256([]byte(fakeKubectlContent("v1.28.3", "linux", "amd64")))
+	if checksum != hex.EncodeToString(sum[:]) {
+		t.Errorf("BinaryChecksum() = %s, want %x", checksum, sum)
+	}
+
+	if err := installer.Install(context.Background(), "v1.28.3"); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if err := installer.VerifyInstalled("v1.28.3", checksum); err != nil {
+		t.Errorf("VerifyInstalled() error = %v", err)
+	}
+
+	// A binary modified after install no longer matches the lock
+	os.WriteFile(filepath.Join(cfg.VersionsDir, "v1.28.3", "kubectl"), []byte("tampered"), 0755)
+	if err := installer.VerifyInstalled("v1.28.3", checksum); err == nil {
+		t.Errorf("Expected VerifyInstalled() to reject a modified binary")
+	}
+}
@@ -0,0 +1,69 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/germainlefebvre4/kuve/pkg/config"
+)
+
+// InstallMetadata records how a version was installed, so that its binaries
+// can be verified later (see Verify)
+type InstallMetadata struct {
+	InstalledAt time.Time `json:"installedAt"`
+	Platform    Platform  `json:"platform"`
+
+	// Checksums holds the SHA-256 checksum of each installed binary by file name
+	Checksums map[string]string `json:"checksums"`
+}
+
+// readMetadata reads the install metadata of a version directory.
+// Versions installed before metadata was recorded yield nil without error.
+func readMetadata(versionDir string) (*InstallMetadata, error) {
+	data, err := os.ReadFile(filepath.Join(versionDir, config.MetadataFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read install metadata: %w", err)
+	}
+
+	var metadata InstallMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("invalid install metadata in %s: %w", versionDir, err)
+	}
+	return &metadata, nil
+}
+
+// recordMetadata hashes the given binaries of a version directory and adds
+// them to its install metadata
+func recordMetadata(versionDir string, platform Platform, binaryNames []string) error {
+	metadata, err := readMetadata(versionDir)
+	if err != nil || metadata == nil {
+		metadata = &InstallMetadata{Platform: platform}
+	}
+	if metadata.Checksums == nil {
+		metadata.Checksums = map[string]string{}
+	}
+	metadata.InstalledAt = time.Now().UTC()
+
+	for _, name := range binaryNames {
+		checksum, err := fileSHA256(filepath.Join(versionDir, name))
+		if err != nil {
+			return fmt.Errorf("failed to compute checksum: %w", err)
+		}
+		metadata.Checksums[name] = checksum
+	}
+
+	data, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(versionDir, config.MetadataFileName), data, 0644); err != nil {
+		return fmt.Errorf("failed to write install metadata: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package kubectl
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/germainlefebvre4/kuve/pkg/config"
+)
+
+// tarballURLTemplate is the URL of a client tarball for a base URL, version, OS and architecture
+const tarballURLTemplate = "%s/%s/kubernetes-client-%s-%s.tar.gz"
+
+// mirrors returns the configured mirrors, defaulting to the official releases
+func (i *Installer) mirrors() []config.Mirror {
+	if len(i.config.Settings.Download.Mirrors) > 0 {
+		return i.config.Settings.Download.Mirrors
+	}
+	return []config.Mirror{{URL: DefaultReleaseURL, Format: config.MirrorFormatBinary}}
+}
+
+// Download downloads the kubectl binary of a version for a platform to destPath
+func (i *Installer) Download(ctx context.Context, version string, platform Platform, destPath string) error {
+	return i.DownloadBinary(ctx, config.KubectlBinaryName, version, platform, destPath)
+}
+
+// DownloadBinary downloads a release binary (the managed tool or a kubectl
+// companion) of a version for a platform to destPath. kubectl is downloaded
+// from the configured mirrors, tried in order until one succeeds; other tools
+// and kubectl without mirrors from the URL of the tool definition.
+// The downloaded artifact is verified against its published checksum unless
+// the mirror or the tool definition disables it.
+func (i *Installer) DownloadBinary(ctx context.Context, name, version string, platform Platform, destPath string) error {
+	tool := i.config.Tool()
+	if !tool.IsKubectl() || len(i.config.Settings.Download.Mirrors) == 0 {
+		return i.downloadFromTool(ctx, tool, name, version, platform, destPath)
+	}
+
+	mirrors := i.mirrors()
+
+	var lastErr error
+	for _, mirror := range mirrors {
+		lastErr = i.downloadFromMirror(ctx, mirror, name, version, platform, destPath)
+		if lastErr == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if len(mirrors) > 1 {
+			fmt.Fprintf(os.Stderr, "Mirror %s failed: %v\n", mirror.URL, lastErr)
+		}
+	}
+
+	return lastErr
+}
+
+// downloadFromMirror downloads a binary from a single mirror. With the tarball
+// format the binary is extracted from the client archive.
+func (i *Installer) downloadFromMirror(ctx context.Context, mirror config.Mirror, name, version string, platform Platform, destPath string) error {
+	baseURL := strings.TrimSuffix(mirror.URL, "/")
+	label := name + " " + version
+
+	switch mirror.Format {
+	case "", config.MirrorFormatBinary:
+		downloadURL := fmt.Sprintf(downloadURLTemplate, baseURL, version, platform.OS, platform.Arch,
+			platform.BinaryName(name))
+		if err := i.downloadFile(ctx, downloadURL, destPath, label); err != nil {
+			return err
+		}
+		if !mirror.SkipChecksum {
+			if err := i.verifyDownload(ctx, downloadURL+".sha256", destPath, ""); err != nil {
+				return err
+			}
+		}
+		return i.verifySignature(ctx, downloadURL, destPath)
+
+	case config.MirrorFormatTarball:
+		archiveURL := fmt.Sprintf(tarballURLTemplate, baseURL, version, platform.OS, platform.Arch)
+		archivePath := filepath.Join(filepath.Dir(destPath), filepath.Base(archiveURL))
+		defer os.Remove(archivePath)
+
+		if err := i.downloadFile(ctx, archiveURL, archivePath, label); err != nil {
+			return err
+		}
+		if !mirror.SkipChecksum {
+			if err := i.verifyDownload(ctx, archiveURL+".sha256", archivePath, ""); err != nil {
+				return err
+			}
+		}
+		if err := i.verifySignature(ctx, archiveURL, archivePath); err != nil {
+			return err
+		}
+
+		archive, err := os.Open(archivePath)
+		if err != nil {
+			return err
+		}
+		defer archive.Close()
+
+		return extractBinary(archive, platform.BinaryName(name), destPath)
+
+	default:
+		return fmt.Errorf("unknown format %q for mirror %s", mirror.Format, mirror.URL)
+	}
+}
+
+// downloadFromTool downloads a binary from the URL of a tool definition,
+// extracting it when the tool is released as an archive
+func (i *Installer) downloadFromTool(ctx context.Context, tool config.Tool, name, version string, platform Platform, destPath string) error {
+	data := tool.URLData(version, platform.OS, platform.Arch)
+	data.Binary = platform.BinaryName(name)
+	label := name + " " + version
+
+	downloadURL, err := tool.ExpandURL(tool.URL, data)
+	if err != nil {
+		return err
+	}
+
+	artifactPath := destPath
+	if tool.Archive != "" {
+		artifactPath = destPath + "." + tool.Archive
+		defer os.Remove(artifactPath)
+	}
+
+	if err := i.downloadFile(ctx, downloadURL, artifactPath, label); err != nil {
+		return err
+	}
+
+	if tool.ChecksumURL != "" {
+		checksumURL, err := tool.ExpandURL(tool.ChecksumURL, data)
+		if err != nil {
+			os.Remove(artifactPath)
+			return err
+		}
+		if err := i.verifyDownload(ctx, checksumURL, artifactPath, artifactFileName(downloadURL)); err != nil {
+			return err
+		}
+	}
+
+	if err := i.verifySignature(ctx, downloadURL, artifactPath); err != nil {
+		return err
+	}
+
+	if tool.Archive == "" {
+		return nil
+	}
+
+	archive, err := os.Open(artifactPath)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	return extractBinary(archive, platform.BinaryName(name), destPath)
+}
+
+// artifactFileName returns the file name of a download URL, as listed in checksum files
+func artifactFileName(downloadURL string) string {
+	if u, err := url.Parse(downloadURL); err == nil {
+		return path.Base(u.Path)
+	}
+	return path.Base(downloadURL)
+}
+
+// verifyDownload checks a downloaded file against a published checksum file,
+// removing the file when it does not match. fileName selects the entry of
+// checksum files listing several artifacts.
+func (i *Installer) verifyDownload(ctx context.Context, checksumURL, path, fileName string) error {
+	expected, err := i.fetchChecksum(ctx, checksumURL, fileName)
+	if err != nil {
+		os.Remove(path)
+		return err
+	}
+	if err := verifyFileSHA256(path, expected); err != nil {
+		os.Remove(path)
+		return err
+	}
+	return nil
+}
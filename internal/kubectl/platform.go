@@ -0,0 +1,56 @@
+package kubectl
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// Platform identifies the operating system and architecture of a kubectl binary
+type Platform struct {
+	OS   string `json:"os"`
+	Arch string `json:"arch"`
+}
+
+// CurrentPlatform returns the platform kuve is running on
+func CurrentPlatform() Platform {
+	return Platform{OS: runtime.GOOS, Arch: runtime.GOARCH}
+}
+
+// ParsePlatform parses a platform written as os/arch (e.g. linux/arm64)
+func ParsePlatform(s string) (Platform, error) {
+	goos, goarch, found := strings.Cut(strings.TrimSpace(s), "/")
+	if !found || goos == "" || goarch == "" || strings.Contains(goarch, "/") {
+		return Platform{}, fmt.Errorf("invalid platform %q, expected os/arch", s)
+	}
+	return Platform{OS: goos, Arch: goarch}, nil
+}
+
+// ParsePlatforms parses a comma separated list of platforms
+func ParsePlatforms(s string) ([]Platform, error) {
+	var platforms []Platform
+	for _, part := range strings.Split(s, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		platform, err := ParsePlatform(part)
+		if err != nil {
+			return nil, err
+		}
+		platforms = append(platforms, platform)
+	}
+	return platforms, nil
+}
+
+// String returns the platform as os/arch
+func (p Platform) String() string {
+	return p.OS + "/" + p.Arch
+}
+
+// BinaryName returns the file name of a binary on this platform
+func (p Platform) BinaryName(name string) string {
+	if p.OS == "windows" {
+		return name + ".exe"
+	}
+	return name
+}
@@ -0,0 +1,176 @@
+package kubectl
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// progressBarWidth is the number of characters of the progress bar
+	progressBarWidth = 30
+
+	// interactiveRefresh is the minimum delay between two progress bar redraws
+	interactiveRefresh = 100 * time.Millisecond
+
+	// logRefresh is the delay between two progress lines in non-interactive output
+	logRefresh = 5 * time.Second
+)
+
+// progressWriter reports the progress of a download. It is meant to be used
+// with an io.TeeReader wrapping the response body.
+// On a terminal it redraws a single progress bar line, otherwise it prints
+// a progress line periodically so that logs stay readable.
+type progressWriter struct {
+	out         io.Writer
+	label       string
+	total       int64 // -1 when the Content-Length is unknown
+	written     int64
+	offset      int64 // bytes already present when resuming a download
+	interactive bool
+	start       time.Time
+	lastReport  time.Time
+	now         func() time.Time
+}
+
+// newProgressWriter creates a progress reporter for a download of total bytes
+func newProgressWriter(out io.Writer, label string, total int64, interactive bool) *progressWriter {
+	p := &progressWriter{
+		out:         out,
+		label:       label,
+		total:       total,
+		interactive: interactive,
+		now:         time.Now,
+	}
+	p.start = p.now()
+	p.lastReport = p.start
+	return p
+}
+
+// resumeFrom accounts for bytes downloaded by a previous attempt
+func (p *progressWriter) resumeFrom(offset int64) {
+	p.offset = offset
+	p.written = offset
+}
+
+// Write records the number of downloaded bytes and reports progress if due
+func (p *progressWriter) Write(b []byte) (int, error) {
+	p.written += int64(len(b))
+
+	now := p.now()
+	interval := logRefresh
+	if p.interactive {
+		interval = interactiveRefresh
+	}
+	if now.Sub(p.lastReport) >= interval {
+		p.lastReport = now
+		p.report(now)
+	}
+
+	return len(b), nil
+}
+
+// Finish prints the final state of the download
+func (p *progressWriter) Finish() {
+	now := p.now()
+	if p.interactive {
+		p.report(now)
+		fmt.Fprintln(p.out)
+		return
+	}
+
+	elapsed := now.Sub(p.start)
+	fmt.Fprintf(p.out, "%s: downloaded %s in %s (%s/s)\n",
+		p.label, formatBytes(p.written), formatDuration(elapsed), formatBytes(p.speed(now)))
+}
+
+// Abort ends the progress bar line after a failed download
+func (p *progressWriter) Abort() {
+	if p.interactive {
+		fmt.Fprintln(p.out)
+	}
+}
+
+// report prints the current progress
+func (p *progressWriter) report(now time.Time) {
+	speed := p.speed(now)
+
+	if p.total <= 0 {
+		line := fmt.Sprintf("%s: %s at %s/s", p.label, formatBytes(p.written), formatBytes(speed))
+		p.print(line)
+		return
+	}
+
+	percent := float64(p.written) / float64(p.total) * 100
+	eta := "--"
+	if speed > 0 {
+		remaining := time.Duration(float64(p.total-p.written)/float64(speed)) * time.Second
+		eta = formatDuration(remaining)
+	}
+
+	if p.interactive {
+		filled := int(float64(progressBarWidth) * float64(p.written) / float64(p.total))
+		if filled > progressBarWidth {
+			filled = progressBarWidth
+		}
+		bar := strings.Repeat("=", filled) + strings.Repeat(" ", progressBarWidth-filled)
+		p.print(fmt.Sprintf("%s [%s] %3.0f%% %s / %s %s/s ETA %s",
+			p.label, bar, percent, formatBytes(p.written), formatBytes(p.total), formatBytes(speed), eta))
+		return
+	}
+
+	p.print(fmt.Sprintf("%s: %.0f%% (%s / %s) at %s/s, ETA %s",
+		p.label, percent, formatBytes(p.written), formatBytes(p.total), formatBytes(speed), eta))
+}
+
+// print writes a progress line, redrawing the current line on a terminal
+func (p *progressWriter) print(line string) {
+	if p.interactive {
+		// Clear the end of the line in case the previous one was longer
+		fmt.Fprintf(p.out, "\r%s\033[K", line)
+		return
+	}
+	fmt.Fprintln(p.out, line)
+}
+
+// speed returns the average download speed in bytes per second
+func (p *progressWriter) speed(now time.Time) int64 {
+	elapsed := now.Sub(p.start).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return int64(float64(p.written-p.offset) / elapsed)
+}
+
+// formatBytes formats a byte count in a human readable form
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// formatDuration formats a duration rounded to the second
+func formatDuration(d time.Duration) string {
+	if d < time.Second {
+		return "0s"
+	}
+	return d.Round(time.Second).String()
+}
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
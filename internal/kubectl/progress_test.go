@@ -0,0 +1,81 @@
+package kubectl
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		input int64
+		want  string
+	}{
+		{input: 0, want: "0 B"},
+		{input: 1023, want: "1023 B"},
+		{input: 1024, want: "1.0 KiB"},
+		{input: 1536, want: "1.5 KiB"},
+		{input: 50 * 1024 * 1024, want: "50.0 MiB"},
+	}
+
+	for _, tt := range tests {
+		if got := formatBytes(tt.input); got != tt.want {
+			t.Errorf("formatBytes(%d) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestProgressWriterNonInteractive(t *testing.T) {
+	var out bytes.Buffer
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+
+	p := newProgressWriter(&out, "kubectl v1.28.0", 4096, false)
+	p.now = func() time.Time { return now }
+	p.start = start
+	p.lastReport = start
+
+	// No line before the log interval has elapsed
+	p.Write(make([]byte, 1024))
+	if out.Len() != 0 {
+		t.Errorf("Expected no output before the refresh interval, got %q", out.String())
+	}
+
+	now = start.Add(logRefresh)
+	p.Write(make([]byte, 1024))
+	if !strings.Contains(out.String(), "kubectl v1.28.0: 50% (2.0 KiB / 4.0 KiB)") {
+		t.Errorf("Unexpected progress line %q", out.String())
+	}
+
+	now = start.Add(2 * logRefresh)
+	p.Write(make([]byte, 2048))
+	p.Finish()
+	if !strings.Contains(out.String(), "kubectl v1.28.0: downloaded 4.0 KiB in 10s") {
+		t.Errorf("Unexpected final line %q", out.String())
+	}
+	if strings.Contains(out.String(), "\r") {
+		t.Errorf("Non-interactive output must not contain carriage returns")
+	}
+}
+
+func TestProgressWriterInteractive(t *testing.T) {
+	var out bytes.Buffer
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	p := newProgressWriter(&out, "kubectl v1.28.0", 2048, true)
+	p.now = func() time.Time { return start.Add(time.Second) }
+	p.start = start
+	p.lastReport = start
+
+	p.Write(make([]byte, 2048))
+	p.Finish()
+
+	got := out.String()
+	if !strings.HasPrefix(got, "\rkubectl v1.28.0 [") {
+		t.Errorf("Expected a redrawn progress bar, got %q", got)
+	}
+	if !strings.Contains(got, "100%") {
+		t.Errorf("Expected 100%% in progress bar, got %q", got)
+	}
+}
@@ -0,0 +1,45 @@
+package kubectl
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/germainlefebvre4/kuve/internal/signature"
+)
+
+// verifySignature checks the cosign signature published next to a downloaded
+// kubectl artifact (<url>.sig and <url>.cert) when VerifySignature is set,
+// removing the file when the signature is missing or invalid. Other tools
+// publish no such signatures and are not checked.
+func (i *Installer) verifySignature(ctx context.Context, artifactURL, path string) error {
+	if !i.VerifySignature || !i.config.Tool().IsKubectl() {
+		return nil
+	}
+
+	if err := i.checkSignature(ctx, artifactURL, path); err != nil {
+		os.Remove(path)
+		return fmt.Errorf("signature verification failed for %s: %w", artifactFileName(artifactURL), err)
+	}
+	return nil
+}
+
+// checkSignature fetches the signature and certificate of an artifact and
+// verifies the downloaded file against them with the configured policy
+func (i *Installer) checkSignature(ctx context.Context, artifactURL, path string) error {
+	policy, err := signature.NewPolicy(i.config.Settings.Signature)
+	if err != nil {
+		return err
+	}
+
+	sig, err := i.fetchSmall(ctx, artifactURL+".sig", "signature")
+	if err != nil {
+		return err
+	}
+	cert, err := i.fetchSmall(ctx, artifactURL+".cert", "certificate")
+	if err != nil {
+		return err
+	}
+
+	return policy.VerifyFile(path, sig, cert)
+}
@@ -0,0 +1,155 @@
+package kubectl
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/germainlefebvre4/kuve/pkg/config"
+)
+
+// smokeTestTimeout bounds the time a binary may take to print its version
+const smokeTestTimeout = 30 * time.Second
+
+// VerifyOptions controls the checks run by Verify
+type VerifyOptions struct {
+	// Upstream compares the binary with the published checksum even when
+	// install metadata is recorded
+	Upstream bool
+}
+
+// VerifyResult holds the outcome of verifying an installed version
+type VerifyResult struct {
+	Version  string
+	Problems []string
+}
+
+// OK reports whether the version passed all checks
+func (r VerifyResult) OK() bool {
+	return len(r.Problems) == 0
+}
+
+// Verify checks the integrity of an installed version: the binaries are
+// hashed and compared with the checksums recorded at install time and, with
+// opts.Upstream or when nothing was recorded, with the published checksum.
+// The binary must then run and report the expected version.
+func (i *Installer) Verify(ctx context.Context, version string, opts VerifyOptions) VerifyResult {
+	result := VerifyResult{Version: version}
+	problem := func(format string, args ...any) {
+		result.Problems = append(result.Problems, fmt.Sprintf(format, args...))
+	}
+
+	tool := i.config.Tool()
+	versionDir := filepath.Join(i.config.VersionsDir, version)
+	binaryName := i.Platform.BinaryName(tool.BinaryName())
+	binaryPath := filepath.Join(versionDir, binaryName)
+
+	info, err := os.Stat(binaryPath)
+	if err != nil {
+		problem("%s binary is missing", tool.Name)
+		return result
+	}
+	if info.IsDir() || info.Mode()&0111 == 0 {
+		problem("%s is not executable", binaryName)
+	}
+
+	metadata, err := readMetadata(versionDir)
+	if err != nil {
+		problem("%v", err)
+	}
+	if metadata != nil {
+		for name, expected := range metadata.Checksums {
+			if err := verifyFileSHA256(filepath.Join(versionDir, name), expected); err != nil {
+				problem("%s does not match the checksum recorded at install: %v", name, err)
+			}
+		}
+	}
+
+	if opts.Upstream || metadata == nil {
+		expected, err := i.BinaryChecksum(ctx, version, i.Platform)
+		if err != nil {
+			problem("could not get the upstream checksum: %v", err)
+		} else if err := verifyFileSHA256(binaryPath, expected); err != nil {
+			problem("%s does not match the upstream checksum: %v", binaryName, err)
+		}
+	}
+
+	if err := i.smokeTest(ctx, binaryPath, version); err != nil {
+		problem("smoke test failed: %v", err)
+	}
+
+	return result
+}
+
+// smokeTest runs the binary and checks that it reports the expected version
+func (i *Installer) smokeTest(ctx context.Context, binaryPath, version string) error {
+	ctx, cancel := context.WithTimeout(ctx, smokeTestTimeout)
+	defer cancel()
+
+	tool := i.config.Tool()
+	if tool.IsKubectl() {
+		reported, err := clientVersion(ctx, binaryPath)
+		if err != nil {
+			return err
+		}
+		if reported != version {
+			return fmt.Errorf("binary reports %s", reported)
+		}
+		return nil
+	}
+
+	args := tool.VersionArgs
+	if len(args) == 0 {
+		args = []string{"version"}
+	}
+	output, err := exec.CommandContext(ctx, binaryPath, args...).CombinedOutput()
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(string(output), strings.TrimPrefix(version, "v")) {
+		return fmt.Errorf("version %s not found in the output of '%s %s'", version, tool.BinaryName(), strings.Join(args, " "))
+	}
+	return nil
+}
+
+// Quarantine moves an installed version out of the versions directory so
+// that it can no longer be used, and returns its new location. The active
+// symlink is removed when it points to the quarantined version. Pinned
+// versions are only quarantined when force is set.
+func (i *Installer) Quarantine(version string, force bool) (string, error) {
+	if err := config.ValidateVersion(version); err != nil {
+		return "", err
+	}
+
+	versionDir := filepath.Join(i.config.VersionsDir, version)
+	if _, err := os.Stat(versionDir); os.IsNotExist(err) {
+		return "", errorf(ErrNotInstalled, "version %s is not installed", version)
+	}
+
+	if !force && i.config.IsPinned(version) {
+		return "", fmt.Errorf("cannot quarantine %s as it is pinned. Run 'kuve unpin %s' or use --force", version, version)
+	}
+
+	quarantineDir := i.config.QuarantineDir()
+	if err := os.MkdirAll(quarantineDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create quarantine directory: %w", err)
+	}
+
+	dest := filepath.Join(quarantineDir, version+"-"+time.Now().UTC().Format("20060102T150405Z"))
+	if err := os.Rename(versionDir, dest); err != nil {
+		return "", fmt.Errorf("failed to quarantine %s: %w", version, err)
+	}
+
+	if target, err := os.Readlink(i.config.CurrentSymlink); err == nil && filepath.Dir(target) == versionDir {
+		if err := os.Remove(i.config.CurrentSymlink); err != nil {
+			return dest, fmt.Errorf("failed to remove active symlink: %w", err)
+		}
+		fmt.Fprintf(i.Out, "Warning: %s was the active version, no %s version is active anymore\n", version, i.config.Tool().Name)
+	}
+
+	return dest, nil
+}
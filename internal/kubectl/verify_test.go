@@ -0,0 +1,96 @@
+package kubectl
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/germainlefebvre4/kuve/pkg/config"
+)
+
+func TestVerifyAndQuarantine(t *testing.T) {
+	cfg := newTestConfig(t)
+	installer := NewInstaller(cfg)
+
+	src := filepath.Join(t.TempDir(), "kubectl")
+	os.WriteFile(src, []byte(fakeKubectl), 0755)
+	if _, err := installer.Import(context.Background(), src, ImportOptions{}); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	result := installer.Verify(context.Background(), "v1.28.3", VerifyOptions{})
+	if !result.OK() {
+		t.Fatalf("Verify() problems = %v, want none", result.Problems)
+	}
+
+	// Tamper with the binary while keeping it runnable
+	binaryPath := filepath.Join(cfg.VersionsDir, "v1.28.3", config.KubectlBinaryName)
+	os.WriteFile(binaryPath, []byte(fakeKubectl+"# tampered\n"), 0755)
+
+	result = installer.Verify(context.Background(), "v1.28.3", VerifyOptions{})
+	if result.OK() || !strings.Contains(result.Problems[0], "recorded at install") {
+		t.Fatalf("Verify() problems = %v, want a checksum mismatch", result.Problems)
+	}
+
+	if err := installer.Switch("v1.28.3"); err != nil {
+		t.Fatalf("Switch() error = %v", err)
+	}
+
+	// Pinned versions are only quarantined with force
+	if err := installer.Pin("v1.28.3"); err != nil {
+		t.Fatalf("Pin() error = %v", err)
+	}
+	if _, err := installer.Quarantine("v1.28.3", false); err == nil || !strings.Contains(err.Error(), "pinned") {
+		t.Fatalf("Quarantine() error = %v, want a pinned version error", err)
+	}
+	if _, err := os.Stat(binaryPath); err != nil {
+		t.Fatalf("Expected the pinned version to stay installed: %v", err)
+	}
+
+	dest, err := installer.Quarantine("v1.28.3", true)
+	if err != nil {
+		t.Fatalf("Quarantine() error = %v", err)
+	}
+	if !strings.HasPrefix(dest, cfg.QuarantineDir()) {
+		t.Errorf("Quarantine() = %s, want a path in %s", dest, cfg.QuarantineDir())
+	}
+	if _, err := os.Stat(filepath.Join(dest, config.KubectlBinaryName)); err != nil {
+		t.Errorf("Expected the binary in quarantine: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.VersionsDir, "v1.28.3")); !os.IsNotExist(err) {
+		t.Errorf("Expected the version to be removed from the versions directory")
+	}
+	if _, err := os.Lstat(cfg.CurrentSymlink); !os.IsNotExist(err) {
+		t.Errorf("Expected the active symlink to be removed")
+	}
+}
+
+func TestQuarantineRejectsInvalidVersions(t *testing.T) {
+	cfg := newTestConfig(t)
+	installer := NewInstaller(cfg)
+
+	outside := filepath.Join(filepath.Dir(cfg.VersionsDir), "outside")
+	os.MkdirAll(outside, 0755)
+	for _, version := range []string{"", "../outside", "v1.28"} {
+		if _, err := installer.Quarantine(version, true); err == nil {
+			t.Errorf("Quarantine(%q) succeeded, want an invalid version error", version)
+		}
+	}
+	if _, err := os.Stat(outside); err != nil {
+		t.Errorf("Quarantine() moved a directory outside the versions directory")
+	}
+}
+
+func TestVerifyMissingBinary(t *testing.T) {
+	cfg := newTestConfig(t)
+	installer := NewInstaller(cfg)
+
+	os.MkdirAll(filepath.Join(cfg.VersionsDir, "v1.28.3"), 0755)
+
+	result := installer.Verify(context.Background(), "v1.28.3", VerifyOptions{})
+	if result.OK() {
+		t.Errorf("Expected Verify() to report the missing binary")
+	}
+}
@@ -0,0 +1,236 @@
+// Package signature verifies the cosign keyless signatures published next to
+// Kubernetes release artifacts (<artifact>.sig and <artifact>.cert).
+//
+// The signing certificate must chain to a trusted Fulcio root and name the
+// expected signer identity and OIDC issuer; the signature must cover the
+// SHA-256 digest of the artifact. The Rekor transparency log is not queried,
+// so verification works offline.
+package signature
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	_ "embed"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/germainlefebvre4/kuve/pkg/config"
+)
+
+var (
+	// fulcioRoots are the root certificates of the Sigstore public-good Fulcio CA
+	//go:embed fulcio_roots.pem
+	fulcioRoots []byte
+
+	// fulcioIntermediates are the intermediate certificates of the Sigstore public-good Fulcio CA
+	//go:embed fulcio_intermediates.pem
+	fulcioIntermediates []byte
+)
+
+var (
+	// oidIssuer is the Fulcio extension holding the OIDC issuer as a raw string
+	oidIssuer = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+
+	// oidIssuerV2 is the Fulcio extension holding the OIDC issuer as a DER UTF8String
+	oidIssuerV2 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+)
+
+// Policy describes the signer trusted for release artifacts
+type Policy struct {
+	// Identity is the expected e-mail or URI subject of the signing certificate
+	Identity string
+
+	// Issuer is the expected OIDC issuer of the signing certificate
+	Issuer string
+
+	Roots         *x509.CertPool
+	Intermediates *x509.CertPool
+}
+
+// NewPolicy builds the policy of the signature settings. Without a roots
+// file, the built-in Sigstore public-good roots are trusted.
+func NewPolicy(settings config.SignatureSettings) (*Policy, error) {
+	policy := &Policy{
+		Identity:      settings.Identity,
+		Issuer:        settings.Issuer,
+		Roots:         x509.NewCertPool(),
+		Intermediates: x509.NewCertPool(),
+	}
+	if policy.Identity == "" || policy.Issuer == "" {
+		return nil, fmt.Errorf("signature identity and issuer are required")
+	}
+
+	if settings.Roots == "" {
+		policy.Roots.AppendCertsFromPEM(fulcioRoots)
+		policy.Intermediates.AppendCertsFromPEM(fulcioIntermediates)
+		return policy, nil
+	}
+
+	data, err := os.ReadFile(settings.Roots)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signature roots: %w", err)
+	}
+	if err := policy.AddCertificates(data); err != nil {
+		return nil, fmt.Errorf("invalid signature roots %s: %w", settings.Roots, err)
+	}
+	return policy, nil
+}
+
+// AddCertificates adds PEM certificates to the policy: self-signed
+// certificates become roots, the others intermediates
+func (p *Policy) AddCertificates(data []byte) error {
+	found := false
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return err
+		}
+		if cert.CheckSignatureFrom(cert) == nil {
+			p.Roots.AddCert(cert)
+		} else {
+			p.Intermediates.AddCert(cert)
+		}
+		found = true
+	}
+
+	if !found {
+		return fmt.Errorf("no certificate found")
+	}
+	return nil
+}
+
+// VerifyFile checks the signature of the file at path. sig and cert are the
+// contents of the published .sig (base64) and .cert (PEM, possibly base64
+// encoded) files.
+func (p *Policy) VerifyFile(path string, sig, cert []byte) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return p.Verify(f, sig, cert)
+}
+
+// Verify checks the signature of an artifact
+func (p *Policy) Verify(artifact io.Reader, sig, cert []byte) error {
+	leaf, err := parseCertificate(cert)
+	if err != nil {
+		return err
+	}
+
+	// Fulcio certificates are only valid for a few minutes around the
+	// signature, so the chain is checked at the time the certificate was issued
+	if _, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         p.Roots,
+		Intermediates: p.Intermediates,
+		CurrentTime:   leaf.NotBefore,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}); err != nil {
+		return fmt.Errorf("untrusted signing certificate: %w", err)
+	}
+
+	if err := p.checkIdentity(leaf); err != nil {
+		return err
+	}
+
+	signature, err := decodeBase64(sig)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(h, artifact); err != nil {
+		return fmt.Errorf("failed to hash artifact: %w", err)
+	}
+
+	publicKey, ok := leaf.PublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("unsupported signing key type %T", leaf.PublicKey)
+	}
+	if !ecdsa.VerifyASN1(publicKey, h.Sum(nil), signature) {
+		return fmt.Errorf("signature does not match the artifact")
+	}
+
+	return nil
+}
+
+// checkIdentity checks the subject and OIDC issuer of the signing certificate
+func (p *Policy) checkIdentity(cert *x509.Certificate) error {
+	subjects := slices.Clone(cert.EmailAddresses)
+	for _, uri := range cert.URIs {
+		subjects = append(subjects, uri.String())
+	}
+	if !slices.Contains(subjects, p.Identity) {
+		return fmt.Errorf("certificate identity %s does not match the expected signer %s",
+			strings.Join(subjects, ", "), p.Identity)
+	}
+
+	issuer := certificateIssuer(cert)
+	if issuer != p.Issuer {
+		return fmt.Errorf("certificate issuer %q does not match the expected issuer %q", issuer, p.Issuer)
+	}
+	return nil
+}
+
+// certificateIssuer returns the OIDC issuer recorded in a Fulcio certificate
+func certificateIssuer(cert *x509.Certificate) string {
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(oidIssuerV2) {
+			var issuer string
+			if _, err := asn1.UnmarshalWithParams(ext.Value, &issuer, "utf8"); err == nil {
+				return issuer
+			}
+		}
+	}
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(oidIssuer) {
+			return string(ext.Value)
+		}
+	}
+	return ""
+}
+
+// parseCertificate parses a PEM certificate, itself possibly base64 encoded
+// as published by the Kubernetes release process
+func parseCertificate(data []byte) (*x509.Certificate, error) {
+	if !strings.Contains(string(data), "-----BEGIN") {
+		decoded, err := decodeBase64(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate: %w", err)
+		}
+		data = decoded
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("invalid certificate: no PEM certificate found")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate: %w", err)
+	}
+	return cert, nil
+}
+
+// decodeBase64 decodes base64 content, ignoring surrounding whitespace
+func decodeBase64(data []byte) ([]byte, error) {
+	return base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+}
@@ -0,0 +1,203 @@
+package signature
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/germainlefebvre4/kuve/pkg/config"
+)
+
+// fixture is an offline stand-in for the Fulcio CA and a signed artifact
+type fixture struct {
+	policy   *Policy
+	artifact []byte
+	sig      []byte
+	cert     []byte
+}
+
+// newCA creates a CA certificate, self-signed when parent is nil
+func newCA(t *testing.T, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{Organization: []string{"kuve-test"}, CommonName: name},
+		NotBefore:             time.Now().Add(-24 * time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return cert, key
+}
+
+// newFixture signs an artifact with a short-lived certificate for the given
+// identity and issuer, issued through a test root and intermediate
+func newFixture(t *testing.T, identity, issuer string) *fixture {
+	t.Helper()
+
+	root, rootKey := newCA(t, "root", nil, nil)
+	intermediate, intermediateKey := newCA(t, "intermediate", root, rootKey)
+
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	issuerV2, _ := asn1.MarshalWithParams(issuer, "utf8")
+	// Like Fulcio certificates, the leaf expired minutes after signing
+	notBefore := time.Now().Add(-time.Hour)
+	template := &x509.Certificate{
+		SerialNumber:   big.NewInt(time.Now().UnixNano()),
+		NotBefore:      notBefore,
+		NotAfter:       notBefore.Add(10 * time.Minute),
+		KeyUsage:       x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		EmailAddresses: []string{identity},
+		ExtraExtensions: []pkix.Extension{
+			{Id: oidIssuer, Value: []byte(issuer)},
+			{Id: oidIssuerV2, Value: issuerV2},
+		},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, intermediate, &leafKey.PublicKey, intermediateKey)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+
+	artifact := []byte("kubectl binary")
+	digest := sha256.Sum256(artifact)
+	sig, err := ecdsa.SignASN1(rand.Reader, leafKey, digest[:])
+	if err != nil {
+		t.Fatalf("Failed to sign artifact: %v", err)
+	}
+
+	policy := &Policy{
+		Identity:      config.KubernetesReleaseSigner,
+		Issuer:        config.KubernetesReleaseSignerIssuer,
+		Roots:         x509.NewCertPool(),
+		Intermediates: x509.NewCertPool(),
+	}
+	var roots bytes.Buffer
+	pem.Encode(&roots, &pem.Block{Type: "CERTIFICATE", Bytes: root.Raw})
+	pem.Encode(&roots, &pem.Block{Type: "CERTIFICATE", Bytes: intermediate.Raw})
+	if err := policy.AddCertificates(roots.Bytes()); err != nil {
+		t.Fatalf("AddCertificates() error = %v", err)
+	}
+
+	// The release process publishes both files base64 encoded
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	return &fixture{
+		policy:   policy,
+		artifact: artifact,
+		sig:      []byte(base64.StdEncoding.EncodeToString(sig) + "\n"),
+		cert:     []byte(base64.StdEncoding.EncodeToString(certPEM)),
+	}
+}
+
+func TestVerify(t *testing.T) {
+	f := newFixture(t, config.KubernetesReleaseSigner, config.KubernetesReleaseSignerIssuer)
+
+	if err := f.policy.Verify(bytes.NewReader(f.artifact), f.sig, f.cert); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	// A raw PEM certificate is accepted as well
+	rawCert, _ := base64.StdEncoding.DecodeString(string(f.cert))
+	if err := f.policy.Verify(bytes.NewReader(f.artifact), f.sig, rawCert); err != nil {
+		t.Errorf("Verify() with a PEM certificate error = %v", err)
+	}
+
+	if err := f.policy.Verify(strings.NewReader("tampered"), f.sig, f.cert); err == nil {
+		t.Errorf("Expected Verify() to reject a modified artifact")
+	}
+}
+
+func TestVerifyRejectsWrongSigner(t *testing.T) {
+	tests := []struct {
+		name     string
+		identity string
+		issuer   string
+		want     string
+	}{
+		{
+			name:     "wrong identity",
+			identity: "attacker@example.com",
+			issuer:   config.KubernetesReleaseSignerIssuer,
+			want:     "identity",
+		},
+		{
+			name:     "wrong issuer",
+			identity: config.KubernetesReleaseSigner,
+			issuer:   "https://token.actions.githubusercontent.com",
+			want:     "issuer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t, tt.identity, tt.issuer)
+			err := f.policy.Verify(bytes.NewReader(f.artifact), f.sig, f.cert)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Verify() error = %v, want a %s mismatch", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyRejectsUntrustedRoot(t *testing.T) {
+	f := newFixture(t, config.KubernetesReleaseSigner, config.KubernetesReleaseSignerIssuer)
+	other := newFixture(t, config.KubernetesReleaseSigner, config.KubernetesReleaseSignerIssuer)
+
+	// Signed through a CA the policy does not trust
+	if err := f.policy.Verify(bytes.NewReader(other.artifact), other.sig, other.cert); err == nil {
+		t.Errorf("Expected Verify() to reject a certificate from an untrusted CA")
+	}
+}
+
+func TestNewPolicyDefaultRoots(t *testing.T) {
+	policy, err := NewPolicy(config.DefaultSettings().Signature)
+	if err != nil {
+		t.Fatalf("NewPolicy() error = %v", err)
+	}
+	if policy.Identity != config.KubernetesReleaseSigner || policy.Issuer != config.KubernetesReleaseSignerIssuer {
+		t.Errorf("NewPolicy() = %s/%s, want the Kubernetes release signer", policy.Identity, policy.Issuer)
+	}
+
+	// The built-in certificates are valid PEM
+	for _, data := range [][]byte{fulcioRoots, fulcioIntermediates} {
+		if err := policy.AddCertificates(data); err != nil {
+			t.Errorf("Invalid built-in Fulcio certificates: %v", err)
+		}
+	}
+
+	if _, err := NewPolicy(config.SignatureSettings{Issuer: "x"}); err == nil {
+		t.Errorf("Expected NewPolicy() to require an identity")
+	}
+}
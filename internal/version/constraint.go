@@ -0,0 +1,216 @@
+package version
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Constraint is a version range such as ">=1.27.0 <1.30.0", "~1.28" or
+// "^1.28.3". Terms separated by spaces or commas must all match, and
+// alternatives are separated by "||". Pre-release suffixes of the bounds,
+// like the "-0" of Helm kubeVersion constraints, are ignored.
+type Constraint struct {
+	raw    string
+	groups [][]constraintTerm
+}
+
+// constraintTerm compares a version with a bound
+type constraintTerm struct {
+	op    string
+	bound [3]int
+
+	// parts is the number of components given in the bound (1.28 has 2)
+	parts int
+}
+
+// constraintOps are the comparison operators, longest first
+var constraintOps = []string{">=", "<=", "!=", "==", ">", "<", "=", "~", "^"}
+
+// ParseConstraint parses a version constraint
+func ParseConstraint(s string) (*Constraint, error) {
+	c := &Constraint{raw: strings.TrimSpace(s)}
+	if c.raw == "" {
+		return nil, fmt.Errorf("version constraint cannot be empty")
+	}
+
+	for _, alternative := range strings.Split(c.raw, "||") {
+		fields := strings.Fields(strings.ReplaceAll(alternative, ",", " "))
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("invalid version constraint %q", s)
+		}
+
+		var group []constraintTerm
+		for idx := 0; idx < len(fields); idx++ {
+			field := fields[idx]
+			// Allow a space between the operator and the version (">= 1.27")
+			if isConstraintOp(field) && idx+1 < len(fields) {
+				idx++
+				field += fields[idx]
+			}
+			term, err := parseConstraintTerm(field)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version constraint %q: %w", s, err)
+			}
+			group = append(group, term)
+		}
+		c.groups = append(c.groups, group)
+	}
+	return c, nil
+}
+
+// isConstraintOp reports whether s is an operator alone
+func isConstraintOp(s string) bool {
+	for _, op := range constraintOps {
+		if s == op {
+			return true
+		}
+	}
+	return false
+}
+
+// parseConstraintTerm parses an operator followed by a version
+func parseConstraintTerm(s string) (constraintTerm, error) {
+	term := constraintTerm{op: "="}
+	for _, op := range constraintOps {
+		if strings.HasPrefix(s, op) {
+			term.op = op
+			s = s[len(op):]
+			break
+		}
+	}
+	if term.op == "==" {
+		term.op = "="
+	}
+
+	s = strings.TrimPrefix(s, "v")
+	if idx := strings.IndexAny(s, "-+"); idx >= 0 {
+		s = s[:idx]
+	}
+
+	for _, part := range strings.SplitN(s, ".", 3) {
+		if part == "x" || part == "X" || part == "*" {
+			break
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return term, fmt.Errorf("invalid version %q", s)
+		}
+		term.bound[term.parts] = n
+		term.parts++
+	}
+	if term.parts == 0 {
+		return term, fmt.Errorf("missing version after %q", term.op)
+	}
+	return term, nil
+}
+
+// Check reports whether a vMAJOR.MINOR.PATCH version satisfies the constraint
+func (c *Constraint) Check(version string) bool {
+	v, err := parseVersionNumbers(version)
+	if err != nil {
+		return false
+	}
+
+	for _, group := range c.groups {
+		matches := true
+		for _, term := range group {
+			if !term.check(v) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the constraint as written
+func (c *Constraint) String() string {
+	return c.raw
+}
+
+// check compares a version with the bound of the term
+func (t constraintTerm) check(v [3]int) bool {
+	cmp := compareNumbers(v, t.bound)
+	switch t.op {
+	case ">":
+		// A partial bound excludes all its versions (>1.28 means >=1.29)
+		return compareNumbers(v, t.upper()) >= 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		// A partial bound includes all its versions (<=1.28 means <1.29)
+		return compareNumbers(v, t.upper()) < 0
+	case "!=":
+		return !t.prefixOf(v)
+	case "~":
+		upper := [3]int{t.bound[0], t.bound[1] + 1, 0}
+		if t.parts == 1 {
+			upper = [3]int{t.bound[0] + 1, 0, 0}
+		}
+		return cmp >= 0 && compareNumbers(v, upper) < 0
+	case "^":
+		upper := [3]int{t.bound[0] + 1, 0, 0}
+		if t.bound[0] == 0 && t.parts > 1 {
+			upper = [3]int{0, t.bound[1] + 1, 0}
+		}
+		return cmp >= 0 && compareNumbers(v, upper) < 0
+	}
+	return t.prefixOf(v)
+}
+
+// prefixOf reports whether the version starts with the components of the bound
+func (t constraintTerm) prefixOf(v [3]int) bool {
+	for idx := 0; idx < t.parts; idx++ {
+		if v[idx] != t.bound[idx] {
+			return false
+		}
+	}
+	return true
+}
+
+// upper returns the first version after those matching a partial bound
+func (t constraintTerm) upper() [3]int {
+	switch t.parts {
+	case 1:
+		return [3]int{t.bound[0] + 1, 0, 0}
+	case 2:
+		return [3]int{t.bound[0], t.bound[1] + 1, 0}
+	}
+	return [3]int{t.bound[0], t.bound[1], t.bound[2] + 1}
+}
+
+// parseVersionNumbers parses the numbers of a vMAJOR.MINOR.PATCH version
+func parseVersionNumbers(version string) ([3]int, error) {
+	var v [3]int
+	parts := strings.Split(strings.TrimPrefix(version, "v"), ".")
+	if len(parts) != 3 {
+		return v, fmt.Errorf("invalid version %q", version)
+	}
+	for idx, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return v, fmt.Errorf("invalid version %q", version)
+		}
+		v[idx] = n
+	}
+	return v, nil
+}
+
+// compareNumbers compares two parsed versions and returns -1, 0 or +1
+func compareNumbers(a, b [3]int) int {
+	for idx := range a {
+		if a[idx] != b[idx] {
+			if a[idx] < b[idx] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,49 @@
+package version
+
+import "testing"
+
+func TestConstraintCheck(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		want       bool
+	}{
+		{constraint: ">=1.27.0-0 <1.30.0-0", version: "v1.27.0", want: true},
+		{constraint: ">=1.27.0-0 <1.30.0-0", version: "v1.29.15", want: true},
+		{constraint: ">=1.27.0-0 <1.30.0-0", version: "v1.30.0", want: false},
+		{constraint: ">=1.27.0-0 <1.30.0-0", version: "v1.26.9", want: false},
+		{constraint: ">= 1.27, < 1.29", version: "v1.28.3", want: true},
+		{constraint: "1.28", version: "v1.28.7", want: true},
+		{constraint: "v1.28.3", version: "v1.28.4", want: false},
+		{constraint: "1.28.x", version: "v1.28.4", want: true},
+		{constraint: "~1.28.3", version: "v1.28.9", want: true},
+		{constraint: "~1.28.3", version: "v1.29.0", want: false},
+		{constraint: "^1.28.3", version: "v1.31.0", want: true},
+		{constraint: "^1.28.3", version: "v1.28.2", want: false},
+		{constraint: ">1.28", version: "v1.28.9", want: false},
+		{constraint: ">1.28", version: "v1.29.0", want: true},
+		{constraint: "<=1.28", version: "v1.28.9", want: true},
+		{constraint: "!=1.28.1", version: "v1.28.1", want: false},
+		{constraint: "1.26 || >=1.29", version: "v1.26.3", want: true},
+		{constraint: "1.26 || >=1.29", version: "v1.27.3", want: false},
+		{constraint: ">=1.27", version: "latest", want: false},
+	}
+
+	for _, tt := range tests {
+		c, err := ParseConstraint(tt.constraint)
+		if err != nil {
+			t.Fatalf("ParseConstraint(%q) error = %v", tt.constraint, err)
+		}
+		if got := c.Check(tt.version); got != tt.want {
+			t.Errorf("%q.Check(%q) = %v, want %v", tt.constraint, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestParseConstraintErrors(t *testing.T) {
+	for _, s := range []string{"", ">=", ">=abc", "1.27 ||"} {
+		if _, err := ParseConstraint(s); err == nil {
+			t.Errorf("ParseConstraint(%q) succeeded, want an error", s)
+		}
+	}
+}
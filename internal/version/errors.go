@@ -0,0 +1,15 @@
+package version
+
+import "errors"
+
+// Errors reported by the version manager, to be tested with errors.Is
+var (
+	// ErrNotFound reports a version the release sources do not know
+	ErrNotFound = errors.New("version not found upstream")
+
+	// ErrNetwork reports a release source that could not be reached or failed to answer
+	ErrNetwork = errors.New("network failure")
+
+	// ErrNoVersionFile reports that no version file applies to the current directory
+	ErrNoVersionFile = errors.New("no version file found")
+)
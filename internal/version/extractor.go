@@ -0,0 +1,196 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/germainlefebvre4/kuve/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+// chartExtractor reads the kubeVersion constraint of a Helm chart
+var chartExtractor = config.Extractor{
+	Name:     FormatChart,
+	File:     "Chart.yaml",
+	JSONPath: "$.kubeVersion",
+	Type:     config.ExtractorTypeConstraint,
+}
+
+// mustExtractor returns the reader of a built-in extractor
+func mustExtractor(e config.Extractor) projectReader {
+	read, err := newExtractor(e)
+	if err != nil {
+		panic(err)
+	}
+	return read
+}
+
+// newExtractor returns the reader of an extractor, which reads the value
+// selected by its regular expression or JSONPath expression in its file
+func newExtractor(e config.Extractor) (projectReader, error) {
+	if e.Name == "" {
+		return nil, fmt.Errorf("extractor of %q: name is required", e.File)
+	}
+	if e.File == "" || filepath.Base(e.File) != e.File {
+		return nil, fmt.Errorf("extractor %s: file must be a file name, got %q", e.Name, e.File)
+	}
+
+	toolName := e.Tool
+	if toolName == "" {
+		toolName = config.DefaultToolName
+	}
+
+	valueType := e.Type
+	switch valueType {
+	case "":
+		valueType = config.ExtractorTypeConstraint
+	case config.ExtractorTypeConstraint, config.ExtractorTypeVersion:
+	default:
+		return nil, fmt.Errorf("extractor %s: type must be %q or %q", e.Name, config.ExtractorTypeConstraint, config.ExtractorTypeVersion)
+	}
+
+	var extract func(data []byte) (string, error)
+	switch {
+	case e.Regex != "" && e.JSONPath != "":
+		return nil, fmt.Errorf("extractor %s: set either regex or jsonPath", e.Name)
+	case e.Regex != "":
+		re, err := regexp.Compile(e.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("extractor %s: invalid regex: %w", e.Name, err)
+		}
+		extract = func(data []byte) (string, error) {
+			return extractRegex(re, data), nil
+		}
+	case e.JSONPath != "":
+		path, err := parseJSONPath(e.JSONPath)
+		if err != nil {
+			return nil, fmt.Errorf("extractor %s: %w", e.Name, err)
+		}
+		extract = func(data []byte) (string, error) {
+			return extractJSONPath(path, data)
+		}
+	default:
+		return nil, fmt.Errorf("extractor %s: regex or jsonPath is required", e.Name)
+	}
+
+	return func(dir string, tool config.Tool) (*ProjectVersion, error) {
+		if tool.Name != toolName {
+			return nil, nil
+		}
+
+		path := filepath.Join(dir, e.File)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		value, err := extract(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return nil, nil
+		}
+
+		if valueType == config.ExtractorTypeVersion {
+			return &ProjectVersion{Path: path, Spec: value}, nil
+		}
+		if _, err := ParseConstraint(value); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return &ProjectVersion{Path: path, Constraint: value}, nil
+	}, nil
+}
+
+// extractRegex returns the first capture group of the first match, or the
+// whole match when the expression has no group
+func extractRegex(re *regexp.Regexp, data []byte) string {
+	matches := re.FindSubmatch(data)
+	switch {
+	case matches == nil:
+		return ""
+	case len(matches) > 1:
+		return string(matches[1])
+	}
+	return string(matches[0])
+}
+
+// jsonPathStepRegex matches a step of a JSONPath expression: .key, ['key'], ["key"] or [0]
+var jsonPathStepRegex = regexp.MustCompile(`^(?:\.([^.\[\]]+)|\[\s*'([^']*)'\s*\]|\[\s*"([^"]*)"\s*\]|\[\s*(\d+)\s*\])`)
+
+// jsonPathStep is a map key or, when key is empty, a list index
+type jsonPathStep struct {
+	key   string
+	index int
+}
+
+// parseJSONPath parses the subset of JSONPath selecting a single value:
+// $.a.b, $['a.b'] and $.list[0]. The leading $ is optional.
+func parseJSONPath(expr string) ([]jsonPathStep, error) {
+	rest := strings.TrimPrefix(strings.TrimSpace(expr), "$")
+	if rest != "" && rest[0] != '.' && rest[0] != '[' {
+		rest = "." + rest
+	}
+
+	var steps []jsonPathStep
+	for rest != "" {
+		matches := jsonPathStepRegex.FindStringSubmatch(rest)
+		if matches == nil {
+			return nil, fmt.Errorf("unsupported jsonPath %q", expr)
+		}
+		rest = rest[len(matches[0]):]
+
+		if matches[4] != "" {
+			index, _ := strconv.Atoi(matches[4])
+			steps = append(steps, jsonPathStep{index: index})
+			continue
+		}
+		steps = append(steps, jsonPathStep{key: matches[1] + matches[2] + matches[3]})
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("jsonPath %q selects the whole document", expr)
+	}
+	return steps, nil
+}
+
+// extractJSONPath returns the scalar value selected in a JSON or YAML
+// document, empty when the path does not exist
+func extractJSONPath(steps []jsonPathStep, data []byte) (string, error) {
+	var value any
+	if err := yaml.Unmarshal(data, &value); err != nil {
+		return "", err
+	}
+
+	for _, step := range steps {
+		switch node := value.(type) {
+		case map[string]any:
+			if step.key == "" {
+				return "", nil
+			}
+			value = node[step.key]
+		case []any:
+			if step.key != "" || step.index >= len(node) {
+				return "", nil
+			}
+			value = node[step.index]
+		default:
+			return "", nil
+		}
+	}
+
+	switch value.(type) {
+	case nil:
+		return "", nil
+	case map[string]any, []any:
+		return "", fmt.Errorf("jsonPath does not select a scalar value")
+	}
+	return fmt.Sprint(value), nil
+}
@@ -0,0 +1,173 @@
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/germainlefebvre4/kuve/pkg/config"
+)
+
+func TestExtractors(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "kuve-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	tests := []struct {
+		name           string
+		extractor      config.Extractor
+		file           string
+		content        string
+		tool           string
+		wantSpec       string
+		wantConstraint string
+	}{
+		{
+			name:           "chart kubeVersion",
+			extractor:      chartExtractor,
+			file:           "Chart.yaml",
+			content:        "apiVersion: v2\nname: app\nkubeVersion: \">=1.27.0-0 <1.30.0-0\"\n",
+			tool:           "kubectl",
+			wantConstraint: ">=1.27.0-0 <1.30.0-0",
+		},
+		{
+			name:      "chart without kubeVersion",
+			extractor: chartExtractor,
+			file:      "Chart.yaml",
+			content:   "apiVersion: v2\nname: app\n",
+			tool:      "kubectl",
+		},
+		{
+			name:      "chart for another tool",
+			extractor: chartExtractor,
+			file:      "Chart.yaml",
+			content:   "kubeVersion: \">=1.27.0\"\n",
+			tool:      "helm",
+		},
+		{
+			name:      "regex version",
+			extractor: config.Extractor{Name: "terraform", File: "versions.tf", Regex: `kubernetes_version\s*=\s*"([^"]+)"`, Type: config.ExtractorTypeVersion},
+			file:      "versions.tf",
+			content:   "locals {\n  kubernetes_version = \"1.28.3\"\n}\n",
+			tool:      "kubectl",
+			wantSpec:  "1.28.3",
+		},
+		{
+			name:           "regex without group",
+			extractor:      config.Extractor{Name: "notes", File: "NOTES", Regex: `>=\d+\.\d+`},
+			file:           "NOTES",
+			content:        "requires kubernetes >=1.27\n",
+			tool:           "kubectl",
+			wantConstraint: ">=1.27",
+		},
+		{
+			name:      "json path in JSON",
+			extractor: config.Extractor{Name: "package", Tool: "helm", File: "package.json", JSONPath: "$.engines['helm'].versions[1]", Type: config.ExtractorTypeVersion},
+			file:      "package.json",
+			content:   `{"engines": {"helm": {"versions": ["3.13.0", "3.14.0"]}}}`,
+			tool:      "helm",
+			wantSpec:  "3.14.0",
+		},
+		{
+			name:      "json path missing",
+			extractor: config.Extractor{Name: "package", File: "package.json", JSONPath: "engines.kubectl"},
+			file:      "package.json",
+			content:   `{"engines": {"node": "20"}}`,
+			tool:      "kubectl",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(tmpDir, tt.name)
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				t.Fatalf("Failed to create directory: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, tt.file), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write %s: %v", tt.file, err)
+			}
+
+			read, err := newExtractor(tt.extractor)
+			if err != nil {
+				t.Fatalf("newExtractor() error = %v", err)
+			}
+			project, err := read(dir, config.Tool{Name: tt.tool})
+			if err != nil {
+				t.Fatalf("read() error = %v", err)
+			}
+
+			var spec, constraint string
+			if project != nil {
+				spec, constraint = project.Spec, project.Constraint
+			}
+			if spec != tt.wantSpec || constraint != tt.wantConstraint {
+				t.Errorf("read() = %q, %q; want %q, %q", spec, constraint, tt.wantSpec, tt.wantConstraint)
+			}
+		})
+	}
+}
+
+func TestNewExtractorErrors(t *testing.T) {
+	tests := map[string]config.Extractor{
+		"no name":         {File: "a.yaml", JSONPath: "$.a"},
+		"no expression":   {Name: "a", File: "a.yaml"},
+		"both":            {Name: "a", File: "a.yaml", JSONPath: "$.a", Regex: "a"},
+		"bad regex":       {Name: "a", File: "a.yaml", Regex: "("},
+		"bad json path":   {Name: "a", File: "a.yaml", JSONPath: "$..a"},
+		"path in file":    {Name: "a", File: "dir/a.yaml", JSONPath: "$.a"},
+		"bad value type":  {Name: "a", File: "a.yaml", JSONPath: "$.a", Type: "range"},
+		"whole document":  {Name: "a", File: "a.yaml", JSONPath: "$"},
+		"invalid quoting": {Name: "a", File: "a.yaml", JSONPath: "$['a"},
+	}
+
+	for name, e := range tests {
+		if _, err := newExtractor(e); err == nil {
+			t.Errorf("%s: newExtractor() succeeded, want an error", name)
+		}
+	}
+}
+
+func TestNewProjectFiles(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "kuve-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "Chart.yaml"), []byte("kubeVersion: \">=1.27.0-0\"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write Chart.yaml: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "k8s.txt"), []byte("1.28.3\n"), 0644); err != nil {
+		t.Fatalf("Failed to write k8s.txt: %v", err)
+	}
+	extractor := config.Extractor{Name: "txt", File: "k8s.txt", Regex: `\S+`, Type: config.ExtractorTypeVersion}
+
+	// Chart.yaml is only read when listed, user-defined extractors follow the defaults
+	projectFiles, err := NewProjectFiles(config.Settings{Extractors: []config.Extractor{extractor}})
+	if err != nil {
+		t.Fatalf("NewProjectFiles() error = %v", err)
+	}
+	project, err := projectFiles.Read(tmpDir, config.KubectlTool())
+	if err != nil || project == nil || project.Format != "txt" || project.Spec != "1.28.3" {
+		t.Errorf("Read() = %+v, %v; want the txt extractor", project, err)
+	}
+
+	projectFiles, err = NewProjectFiles(config.Settings{
+		VersionFiles: []string{FormatChart, "txt"},
+		Extractors:   []config.Extractor{extractor},
+	})
+	if err != nil {
+		t.Fatalf("NewProjectFiles() error = %v", err)
+	}
+	project, err = projectFiles.Read(tmpDir, config.KubectlTool())
+	if err != nil || project == nil || project.Format != FormatChart || project.Constraint != ">=1.27.0-0" {
+		t.Errorf("Read() = %+v, %v; want the Chart.yaml constraint", project, err)
+	}
+
+	extractor.Name = FormatMise
+	if _, err := NewProjectFiles(config.Settings{Extractors: []config.Extractor{extractor}}); err == nil {
+		t.Errorf("NewProjectFiles() accepted an extractor named after a built-in format")
+	}
+}
@@ -0,0 +1,139 @@
+package version
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/germainlefebvre4/kuve/pkg/config"
+)
+
+// Triggers of a version switch recorded in the history
+const (
+	// TriggerManual is an explicit 'kuve switch'
+	TriggerManual = "manual"
+
+	// TriggerUse is 'kuve use' reading a version file
+	TriggerUse = "use"
+
+	// TriggerHook is 'kuve use --hook' run by a shell hook
+	TriggerHook = "hook"
+
+	// TriggerCluster is 'kuve use --from-cluster'
+	TriggerCluster = "cluster"
+)
+
+// maxHistoryEntries is the number of switches kept in the history log
+const maxHistoryEntries = 1000
+
+// HistoryEntry records a switch of the active version of a tool
+type HistoryEntry struct {
+	Time time.Time `json:"time" yaml:"time"`
+	Tool string    `json:"tool" yaml:"tool"`
+
+	// From is the version active before the switch, empty if none was
+	From string `json:"from" yaml:"from"`
+
+	To      string `json:"to" yaml:"to"`
+	Trigger string `json:"trigger" yaml:"trigger"`
+}
+
+// ReadHistory reads the switch history, oldest first.
+// A missing history yields no entries without error.
+func ReadHistory(cfg *config.Config) ([]HistoryEntry, error) {
+	data, err := os.ReadFile(cfg.HistoryFile())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	var entries []HistoryEntry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var entry HistoryEntry
+		// Skip lines a concurrent write or an older kuve left unreadable
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err == nil {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// AppendHistory adds an entry to the switch history, dropping the oldest
+// entries once the log exceeds maxHistoryEntries
+func AppendHistory(cfg *config.Config, entry HistoryEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(cfg.HistoryFile(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open history: %w", err)
+	}
+	_, err = f.Write(append(line, '\n'))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+
+	entries, err := ReadHistory(cfg)
+	if err != nil || len(entries) <= maxHistoryEntries {
+		return err
+	}
+	return writeHistory(cfg, entries[len(entries)-maxHistoryEntries:])
+}
+
+// writeHistory replaces the history log with the given entries
+func writeHistory(cfg *config.Config, entries []HistoryEntry) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return err
+		}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(cfg.HistoryFile()), ".history-*")
+	if err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	_, err = tmp.Write(buf.Bytes())
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	return os.Rename(tmp.Name(), cfg.HistoryFile())
+}
+
+// PreviousVersion returns the version a tool was switched from when it was
+// switched to its current version, as 'cd -' returns to the previous directory
+func PreviousVersion(entries []HistoryEntry, tool, current string) (string, error) {
+	for idx := len(entries) - 1; idx >= 0; idx-- {
+		entry := entries[idx]
+		if entry.Tool != tool {
+			continue
+		}
+		if entry.To != current || entry.From == "" {
+			break
+		}
+		return entry.From, nil
+	}
+	return "", fmt.Errorf("no previous %s version in the history", tool)
+}
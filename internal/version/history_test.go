@@ -0,0 +1,92 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/germainlefebvre4/kuve/pkg/config"
+)
+
+func newHistoryConfig(t *testing.T) *config.Config {
+	t.Helper()
+
+	// Create a temporary directory
+	tmpDir, err := os.MkdirTemp("", "kuve-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(tmpDir) })
+
+	return &config.Config{KuveDir: tmpDir}
+}
+
+func TestHistoryAndPreviousVersion(t *testing.T) {
+	cfg := newHistoryConfig(t)
+
+	entries, err := ReadHistory(cfg)
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("ReadHistory() on a missing file = %v, %v; want no entries", entries, err)
+	}
+
+	switches := []HistoryEntry{
+		{Tool: "kubectl", To: "v1.28.0", Trigger: TriggerManual},
+		{Tool: "kubectl", From: "v1.28.0", To: "v1.29.0", Trigger: TriggerUse},
+		{Tool: "helm", From: "v3.13.0", To: "v3.14.0", Trigger: TriggerHook},
+	}
+	for _, entry := range switches {
+		entry.Time = time.Now().UTC()
+		if err := AppendHistory(cfg, entry); err != nil {
+			t.Fatalf("AppendHistory() error = %v", err)
+		}
+	}
+
+	entries, err = ReadHistory(cfg)
+	if err != nil {
+		t.Fatalf("ReadHistory() error = %v", err)
+	}
+	if len(entries) != len(switches) || entries[1].From != "v1.28.0" || entries[1].Trigger != TriggerUse {
+		t.Fatalf("ReadHistory() = %+v", entries)
+	}
+
+	// The switches of other tools are ignored
+	previous, err := PreviousVersion(entries, "kubectl", "v1.29.0")
+	if err != nil || previous != "v1.28.0" {
+		t.Errorf("PreviousVersion() = %q, %v; want v1.28.0", previous, err)
+	}
+
+	// The first switch has nothing to return to
+	if _, err := PreviousVersion(entries[:1], "kubectl", "v1.28.0"); err == nil {
+		t.Errorf("Expected PreviousVersion() to fail without a previous version")
+	}
+
+	// The active version was changed outside of kuve
+	if _, err := PreviousVersion(entries, "kubectl", "v1.27.0"); err == nil {
+		t.Errorf("Expected PreviousVersion() to fail when the history does not end with the current version")
+	}
+}
+
+func TestAppendHistoryDropsOldestEntries(t *testing.T) {
+	cfg := newHistoryConfig(t)
+
+	var entries []HistoryEntry
+	for idx := 0; idx < maxHistoryEntries; idx++ {
+		entries = append(entries, HistoryEntry{Tool: "kubectl", To: fmt.Sprintf("v1.%d.0", idx)})
+	}
+	if err := writeHistory(cfg, entries); err != nil {
+		t.Fatalf("writeHistory() error = %v", err)
+	}
+
+	if err := AppendHistory(cfg, HistoryEntry{Tool: "kubectl", To: "v2.0.0"}); err != nil {
+		t.Fatalf("AppendHistory() error = %v", err)
+	}
+
+	got, err := ReadHistory(cfg)
+	if err != nil {
+		t.Fatalf("ReadHistory() error = %v", err)
+	}
+	if len(got) != maxHistoryEntries || got[0].To != "v1.1.0" || got[len(got)-1].To != "v2.0.0" {
+		t.Errorf("ReadHistory() kept %d entries from %s to %s", len(got), got[0].To, got[len(got)-1].To)
+	}
+}
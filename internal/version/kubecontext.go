@@ -0,0 +1,47 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentKubeContext returns the current context of the kubeconfig, read
+// from the files of $KUBECONFIG (the first one setting it wins, like
+// kubectl) or ~/.kube/config. It returns an empty string when none is set.
+func CurrentKubeContext() (string, error) {
+	paths := filepath.SplitList(os.Getenv("KUBECONFIG"))
+	if len(paths) == 0 {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		paths = []string{filepath.Join(home, ".kube", "config")}
+	}
+
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return "", fmt.Errorf("failed to read kubeconfig: %w", err)
+		}
+
+		var kubeconfig struct {
+			CurrentContext string `yaml:"current-context"`
+		}
+		if err := yaml.Unmarshal(data, &kubeconfig); err != nil {
+			return "", fmt.Errorf("failed to parse kubeconfig %s: %w", path, err)
+		}
+		if kubeconfig.CurrentContext != "" {
+			return kubeconfig.CurrentContext, nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,94 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/germainlefebvre4/kuve/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+// lockFileHeader is written at the top of lock files
+const lockFileHeader = "# Generated by 'kuve lock'. Do not edit.\n"
+
+// LockFile pins the resolved versions and binary checksums of the tools of a project
+type LockFile struct {
+	Tools map[string]LockEntry `yaml:"tools"`
+}
+
+// LockEntry records a resolved version and the SHA-256 checksums of its
+// binary keyed by platform (os/arch)
+type LockEntry struct {
+	// Spec is the content of the version file the entry was resolved from
+	Spec string `yaml:"spec"`
+
+	// Version is the exact version Spec resolved to
+	Version string `yaml:"version"`
+
+	Checksums map[string]string `yaml:"checksums"`
+}
+
+// ReadLockFile reads the lock file of a directory.
+// A missing lock file yields nil without error.
+func ReadLockFile(dir string) (*LockFile, error) {
+	data, err := os.ReadFile(filepath.Join(dir, config.LockFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read lock file: %w", err)
+	}
+
+	var lock LockFile
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse lock file %s: %w", filepath.Join(dir, config.LockFileName), err)
+	}
+	return &lock, nil
+}
+
+// WriteLockFile writes the lock file of a directory
+func WriteLockFile(dir string, lock *LockFile) error {
+	data, err := yaml.Marshal(lock)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, config.LockFileName)
+	if err := os.WriteFile(path, append([]byte(lockFileHeader), data...), 0644); err != nil {
+		return fmt.Errorf("failed to write lock file: %w", err)
+	}
+	return nil
+}
+
+// Resolve returns the locked version for the content of a version file.
+// A lock entry resolved from another specification is stale. Lock files are
+// committed, hence untrusted: the version must be a full version.
+func (e LockEntry) Resolve(spec string) (string, error) {
+	if normalizeSpec(e.Spec) != normalizeSpec(spec) {
+		return "", fmt.Errorf("lock file is out of date: it was generated for %s but the version file requests %s, run 'kuve lock'", e.Spec, spec)
+	}
+	if err := config.ValidateVersion(e.Version); err != nil {
+		return "", fmt.Errorf("invalid lock file: %w", err)
+	}
+	return e.Version, nil
+}
+
+// Checksum returns the locked checksum of a platform
+func (e LockEntry) Checksum(platform string) (string, error) {
+	checksum, ok := e.Checksums[platform]
+	if !ok || checksum == "" {
+		return "", fmt.Errorf("lock file has no checksum for %s, run 'kuve lock --platform %s'", platform, platform)
+	}
+	return checksum, nil
+}
+
+// normalizeSpec makes version specifications comparable (1.28 and v1.28 are equal)
+func normalizeSpec(spec string) string {
+	spec = strings.TrimSpace(spec)
+	if spec != "" && spec[0] != 'v' {
+		spec = "v" + spec
+	}
+	return spec
+}
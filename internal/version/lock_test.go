@@ -0,0 +1,75 @@
+package version
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestLockFileRoundTrip(t *testing.T) {
+	// Create a temporary directory
+	tmpDir, err := os.MkdirTemp("", "kuve-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	lock, err := ReadLockFile(tmpDir)
+	if err != nil || lock != nil {
+		t.Fatalf("ReadLockFile() on a missing file = %v, %v; want nil, nil", lock, err)
+	}
+
+	want := &LockFile{Tools: map[string]LockEntry{
+		"kubectl": {
+			Spec:    "1.28",
+			Version: "v1.28.15",
+			Checksums: map[string]string{
+				"linux/amd64":  "aaaa",
+				"darwin/arm64": "bbbb",
+			},
+		},
+	}}
+	if err := WriteLockFile(tmpDir, want); err != nil {
+		t.Fatalf("WriteLockFile() error = %v", err)
+	}
+
+	got, err := ReadLockFile(tmpDir)
+	if err != nil {
+		t.Fatalf("ReadLockFile() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadLockFile() = %+v, want %+v", got, want)
+	}
+}
+
+func TestLockEntry(t *testing.T) {
+	entry := LockEntry{
+		Spec:      "1.28",
+		Version:   "v1.28.15",
+		Checksums: map[string]string{"linux/amd64": "aaaa"},
+	}
+
+	for _, spec := range []string{"1.28", "v1.28", " 1.28\n"} {
+		version, err := entry.Resolve(spec)
+		if err != nil || version != "v1.28.15" {
+			t.Errorf("Resolve(%q) = %q, %v; want v1.28.15", spec, version, err)
+		}
+	}
+	if _, err := entry.Resolve("1.29"); err == nil {
+		t.Errorf("Expected Resolve() to reject a stale lock entry")
+	}
+
+	for _, locked := range []string{"v1/../../../../tmp/evil", "v1.28.15-dirty", ""} {
+		tampered := LockEntry{Spec: "1.28", Version: locked, Checksums: entry.Checksums}
+		if got, err := tampered.Resolve("1.28"); err == nil {
+			t.Errorf("Resolve() with locked version %q = %q, want an invalid lock file error", locked, got)
+		}
+	}
+
+	if checksum, err := entry.Checksum("linux/amd64"); err != nil || checksum != "aaaa" {
+		t.Errorf("Checksum(linux/amd64) = %q, %v; want aaaa", checksum, err)
+	}
+	if _, err := entry.Checksum("darwin/arm64"); err == nil {
+		t.Errorf("Expected Checksum() to fail for a platform missing from the lock")
+	}
+}
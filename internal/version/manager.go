@@ -2,7 +2,9 @@ package version
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -11,63 +13,184 @@ import (
 	"path/filepath"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/germainlefebvre4/kuve/internal/httpclient"
 	"github.com/germainlefebvre4/kuve/pkg/config"
 )
 
-const (
-	// KubectlReleasesURL is the URL to fetch kubectl releases
-	KubectlReleasesURL         = "https://dl.k8s.io/release/stable.txt"
-	KubectlDownloadURLTemplate = "https://dl.k8s.io/release/%s/bin/%s/%s/kubectl"
-)
+// githubAPIURL is the base URL of the GitHub API listing tool releases
+var githubAPIURL = "https://api.github.com"
+
+var minorVersionRegex = regexp.MustCompile(`^v(\d+)\.(\d+)$`)
 
 // Manager handles version operations
 type Manager struct {
 	config *config.Config
+
+	// client is the shared HTTP client, clientErr reports an invalid HTTP configuration
+	client    *http.Client
+	clientErr error
 }
 
 // NewManager creates a new version manager
 func NewManager(cfg *config.Config) *Manager {
+	client, err := httpclient.New(cfg.Settings.HTTP, cfg.Settings.Download.Mirrors)
 	return &Manager{
-		config: cfg,
+		config:    cfg,
+		client:    client,
+		clientErr: err,
 	}
 }
 
-// GetStableVersion fetches the latest stable kubectl version
-func (m *Manager) GetStableVersion() (string, error) {
-	resp, err := http.Get(KubectlReleasesURL)
+// GetStableVersion fetches the latest stable version of the managed tool,
+// from its stable URL or else from its GitHub releases
+func (m *Manager) GetStableVersion(ctx context.Context) (string, error) {
+	tool := m.config.Tool()
+	if tool.StableURL == "" {
+		versions, err := m.listReleases(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to fetch stable version: %w", err)
+		}
+		if len(versions) == 0 {
+			return "", fmt.Errorf("%w: no stable release found for %s", ErrNotFound, tool.Name)
+		}
+		return versions[0], nil
+	}
+
+	resp, err := m.get(ctx, tool.StableURL)
 	if err != nil {
 		return "", fmt.Errorf("failed to fetch stable version: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: failed to fetch stable version: HTTP %d", ErrNetwork, resp.StatusCode)
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
+		return "", fmt.Errorf("%w: failed to read response: %w", ErrNetwork, err)
 	}
 
 	version := strings.TrimSpace(string(body))
 	return version, nil
 }
 
-// ListRemoteVersions fetches available kubectl versions
+// GetLatestPatchVersion fetches the latest patch release of a minor version (e.g. 1.28 -> v1.28.15)
+func (m *Manager) GetLatestPatchVersion(ctx context.Context, major, minor string) (string, error) {
+	tool := m.config.Tool()
+	if tool.MinorStableURL == "" {
+		return m.latestReleasedPatch(ctx, major, minor)
+	}
+
+	url, err := tool.ExpandURL(tool.MinorStableURL, config.ToolURLData{Major: major, Minor: minor})
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := m.get(ctx, url)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch latest patch version: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: no release found for v%s.%s (HTTP %d)", ErrNotFound, major, minor, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("%w: failed to read response: %w", ErrNetwork, err)
+	}
+
+	version := strings.TrimSpace(string(body))
+	if !config.IsFullVersion(version) {
+		return "", fmt.Errorf("unexpected version %q for v%s.%s", version, major, minor)
+	}
+
+	return version, nil
+}
+
+// latestReleasedPatch finds the latest patch of a minor version in the GitHub releases
+func (m *Manager) latestReleasedPatch(ctx context.Context, major, minor string) (string, error) {
+	versions, err := m.listReleases(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch latest patch version: %w", err)
+	}
+
+	prefix := fmt.Sprintf("v%s.%s.", major, minor)
+	for _, v := range versions {
+		if strings.HasPrefix(v, prefix) {
+			return v, nil
+		}
+	}
+	return "", fmt.Errorf("%w: no release found for v%s.%s", ErrNotFound, major, minor)
+}
+
+// ResolveVersion turns a version specification into an exact version.
+// A minor version (1.28) is resolved to its latest patch release and "stable"
+// or "latest" to the latest stable release. Other versions are only normalized.
+func (m *Manager) ResolveVersion(ctx context.Context, spec string) (string, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return "", fmt.Errorf("version cannot be empty")
+	}
+
+	if spec == "stable" || spec == "latest" {
+		return m.GetStableVersion(ctx)
+	}
+
+	// Normalize version
+	if spec[0] != 'v' {
+		spec = "v" + spec
+	}
+
+	if matches := minorVersionRegex.FindStringSubmatch(spec); matches != nil {
+		return m.GetLatestPatchVersion(ctx, matches[1], matches[2])
+	}
+
+	return spec, nil
+}
+
+// ListRemoteVersions fetches available versions of the managed tool
 // Returns the last 10 stable versions from GitHub releases
-func (m *Manager) ListRemoteVersions() ([]string, error) {
+func (m *Manager) ListRemoteVersions(ctx context.Context) ([]string, error) {
+	versions, err := m.listReleases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(versions) > 10 {
+		versions = versions[:10]
+	}
+	return versions, nil
+}
+
+// listReleases fetches the stable versions of the managed tool from its
+// GitHub releases, newest first. Release tags are stripped of the tool
+// tag prefix; drafts, pre-releases and other tags are ignored.
+func (m *Manager) listReleases(ctx context.Context) ([]string, error) {
+	tool := m.config.Tool()
+	if tool.GitHubRepo == "" {
+		return nil, fmt.Errorf("no release source configured for %s (githubRepo)", tool.Name)
+	}
+
 	// Fetch releases from GitHub API
-	resp, err := http.Get("https://api.github.com/repos/kubernetes/kubernetes/releases?per_page=50")
+	resp, err := m.get(ctx, fmt.Sprintf("%s/repos/%s/releases?per_page=100", githubAPIURL, tool.GitHubRepo))
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch releases from GitHub: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+		return nil, fmt.Errorf("%w: GitHub API returned status %d", ErrNetwork, resp.StatusCode)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, fmt.Errorf("%w: failed to read response: %w", ErrNetwork, err)
 	}
 
 	// Parse JSON response
@@ -83,26 +206,68 @@ func (m *Manager) ListRemoteVersions() ([]string, error) {
 
 	// Filter and collect stable versions (exclude pre-releases, drafts, and RC versions)
 	versions := []string{}
-	versionRegex := regexp.MustCompile(`^v\d+\.\d+\.\d+$`)
-
 	for _, release := range releases {
-		if !release.Draft && !release.Prerelease && versionRegex.MatchString(release.TagName) {
-			versions = append(versions, release.TagName)
-			if len(versions) == 10 {
-				break
-			}
+		if release.Draft || release.Prerelease || !strings.HasPrefix(release.TagName, tool.TagPrefix) {
+			continue
+		}
+		version := strings.TrimPrefix(release.TagName, tool.TagPrefix)
+		if config.IsFullVersion(version) {
+			versions = append(versions, version)
 		}
 	}
 
 	// Sort versions in descending order (newest first)
 	sort.Slice(versions, func(i, j int) bool {
-		return versions[i] > versions[j]
+		return CompareVersions(versions[i], versions[j]) > 0
 	})
 
 	return versions, nil
 }
 
-// ListInstalledVersions lists all locally installed kubectl versions
+// CompareVersions compares two vMAJOR.MINOR.PATCH versions numerically and
+// returns -1, 0 or +1. Missing or non-numeric components count as zero.
+func CompareVersions(a, b string) int {
+	pa := strings.SplitN(strings.TrimPrefix(a, "v"), ".", 3)
+	pb := strings.SplitN(strings.TrimPrefix(b, "v"), ".", 3)
+	for idx := 0; idx < 3; idx++ {
+		var na, nb int
+		if idx < len(pa) {
+			na, _ = strconv.Atoi(pa[idx])
+		}
+		if idx < len(pb) {
+			nb, _ = strconv.Atoi(pb[idx])
+		}
+		if na != nb {
+			if na < nb {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// get performs an HTTP GET request bounded by the configured HTTP timeout
+func (m *Manager) get(ctx context.Context, url string) (*http.Response, error) {
+	if m.clientErr != nil {
+		return nil, fmt.Errorf("invalid HTTP configuration: %w", m.clientErr)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := *m.client
+	client.Timeout = m.config.Settings.Timeouts.HTTP
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNetwork, err)
+	}
+	return resp, nil
+}
+
+// ListInstalledVersions lists all locally installed versions of the managed tool
 func (m *Manager) ListInstalledVersions() ([]string, error) {
 	entries, err := os.ReadDir(m.config.VersionsDir)
 	if err != nil {
@@ -113,10 +278,8 @@ func (m *Manager) ListInstalledVersions() ([]string, error) {
 	}
 
 	versions := []string{}
-	versionRegex := regexp.MustCompile(`^v\d+\.\d+\.\d+$`)
-
 	for _, entry := range entries {
-		if entry.IsDir() && versionRegex.MatchString(entry.Name()) {
+		if entry.IsDir() && config.IsFullVersion(entry.Name()) {
 			versions = append(versions, entry.Name())
 		}
 	}
@@ -125,12 +288,12 @@ func (m *Manager) ListInstalledVersions() ([]string, error) {
 	return versions, nil
 }
 
-// GetCurrentVersion returns the currently active kubectl version
+// GetCurrentVersion returns the currently active version of the managed tool
 func (m *Manager) GetCurrentVersion() (string, error) {
 	target, err := os.Readlink(m.config.CurrentSymlink)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return "", fmt.Errorf("no kubectl version is currently active")
+			return "", fmt.Errorf("no %s version is currently active", m.config.Tool().Name)
 		}
 		return "", fmt.Errorf("failed to read symlink: %w", err)
 	}
@@ -146,12 +309,14 @@ func (m *Manager) GetCurrentVersion() (string, error) {
 	return "", fmt.Errorf("could not determine version from symlink")
 }
 
+// BinaryPath returns the path of the binary of a version, installed or not
+func (m *Manager) BinaryPath(version string) string {
+	return filepath.Join(m.config.VersionsDir, version, m.config.Tool().BinaryName())
+}
+
 // IsVersionInstalled checks if a specific version is installed
 func (m *Manager) IsVersionInstalled(version string) bool {
-	versionDir := filepath.Join(m.config.VersionsDir, version)
-	kubectlBinary := filepath.Join(versionDir, config.KubectlBinaryName)
-
-	info, err := os.Stat(kubectlBinary)
+	info, err := os.Stat(m.BinaryPath(version))
 	if err != nil {
 		return false
 	}
@@ -159,9 +324,30 @@ func (m *Manager) IsVersionInstalled(version string) bool {
 	return !info.IsDir() && info.Mode()&0111 != 0 // Check if it's executable
 }
 
+// IsVersionPinned checks if a specific version is pinned
+func (m *Manager) IsVersionPinned(version string) bool {
+	return m.config.IsPinned(version)
+}
+
+// InstalledCompanions returns the companion binaries installed with a version
+func (m *Manager) InstalledCompanions(version string) []string {
+	var companions []string
+	for _, name := range config.CompanionBinaryNames {
+		if _, err := os.Stat(filepath.Join(m.config.VersionsDir, version, name)); err == nil {
+			companions = append(companions, name)
+		}
+	}
+	return companions
+}
+
 // ReadVersionFile reads the .kubernetes-version file
 func ReadVersionFile(dir string) (string, error) {
-	versionFile := filepath.Join(dir, config.VersionFileName)
+	return ReadVersionFileNamed(dir, config.VersionFileName)
+}
+
+// ReadVersionFileNamed reads the version file of a tool in a directory
+func ReadVersionFileNamed(dir, fileName string) (string, error) {
+	versionFile := filepath.Join(dir, fileName)
 	data, err := os.ReadFile(versionFile)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -180,18 +366,31 @@ func ReadVersionFile(dir string) (string, error) {
 
 // FindVersionFile searches for .kubernetes-version file in current and parent directories
 func FindVersionFile() (string, error) {
+	return FindVersionFileNamed(config.VersionFileName)
+}
+
+// FindVersionFileNamed searches for the version file of a tool in current and parent directories
+func FindVersionFileNamed(fileName string) (string, error) {
+	_, version, err := LocateVersionFile(fileName)
+	return version, err
+}
+
+// LocateVersionFile searches for the version file of a tool in current and
+// parent directories and returns the directory holding it with its version.
+// The directory is empty when no version file is found.
+func LocateVersionFile(fileName string) (dir, version string, err error) {
 	currentDir, err := os.Getwd()
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
 	for {
-		version, err := ReadVersionFile(currentDir)
+		version, err := ReadVersionFileNamed(currentDir, fileName)
 		if err != nil {
-			return "", err
+			return "", "", err
 		}
 		if version != "" {
-			return version, nil
+			return currentDir, version, nil
 		}
 
 		parentDir := filepath.Dir(currentDir)
@@ -202,13 +401,13 @@ func FindVersionFile() (string, error) {
 		currentDir = parentDir
 	}
 
-	return "", nil
+	return "", "", nil
 }
 
 // DetectClusterVersion detects the Kubernetes version from the current cluster context
 // Returns the normalized kubectl version to install
-func (m *Manager) DetectClusterVersion() (string, error) {
-	_, normalizedVersion, err := m.detectClusterVersionRaw()
+func (m *Manager) DetectClusterVersion(ctx context.Context) (string, error) {
+	_, normalizedVersion, err := m.detectClusterVersionRaw(ctx, "")
 	if err != nil {
 		return "", err
 	}
@@ -216,21 +415,39 @@ func (m *Manager) DetectClusterVersion() (string, error) {
 }
 
 // DetectClusterVersionWithRaw detects the Kubernetes version and returns both raw and normalized versions
-func (m *Manager) DetectClusterVersionWithRaw() (rawVersion, normalizedVersion string, err error) {
-	return m.detectClusterVersionRaw()
+func (m *Manager) DetectClusterVersionWithRaw(ctx context.Context) (rawVersion, normalizedVersion string, err error) {
+	return m.detectClusterVersionRaw(ctx, "")
 }
 
-// detectClusterVersionRaw is the internal implementation
-func (m *Manager) detectClusterVersionRaw() (rawVersion, normalizedVersion string, err error) {
+// DetectContextVersion detects the Kubernetes version of the cluster of a
+// kubeconfig context, which need not be the current one, and returns both
+// raw and normalized versions
+func (m *Manager) DetectContextVersion(ctx context.Context, kubeContext string) (rawVersion, normalizedVersion string, err error) {
+	return m.detectClusterVersionRaw(ctx, kubeContext)
+}
+
+// detectClusterVersionRaw is the internal implementation, using the current
+// context when kubeContext is empty
+func (m *Manager) detectClusterVersionRaw(ctx context.Context, kubeContext string) (rawVersion, normalizedVersion string, err error) {
 	// Check if kubectl exists in PATH or in kuve's bin
 	kubectlPath, err := m.findKubectlBinary()
 	if err != nil {
 		return "", "", fmt.Errorf("kubectl not found: %w", err)
 	}
 
+	// Bound the time spent waiting for an unreachable cluster
+	if timeout := m.config.Settings.Timeouts.Cluster; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
 	// Run kubectl version to get server version
-	rawVersion, err = m.getServerVersion(kubectlPath)
+	rawVersion, err = m.getServerVersion(ctx, kubectlPath, kubeContext)
 	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", "", fmt.Errorf("timed out after %s waiting for the cluster", m.config.Settings.Timeouts.Cluster)
+		}
 		return "", "", fmt.Errorf("failed to get cluster version: %w", err)
 	}
 
@@ -260,13 +477,16 @@ func (m *Manager) findKubectlBinary() (string, error) {
 }
 
 // getServerVersion executes kubectl to get the server version
-func (m *Manager) getServerVersion(kubectlPath string) (string, error) {
+func (m *Manager) getServerVersion(ctx context.Context, kubectlPath, kubeContext string) (string, error) {
 	// Run kubectl version with JSON output
-	cmd := exec.Command(kubectlPath, "version", "--output=json")
+	cmd := exec.CommandContext(ctx, kubectlPath, contextArgs(kubeContext, "version", "--output=json")...)
 	output, err := cmd.Output()
 	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
 		// Try fallback with short output
-		return m.getServerVersionFallback(kubectlPath)
+		return m.getServerVersionFallback(ctx, kubectlPath, kubeContext)
 	}
 
 	// Parse JSON output
@@ -277,7 +497,7 @@ func (m *Manager) getServerVersion(kubectlPath string) (string, error) {
 	}
 
 	if err := json.Unmarshal(output, &versionInfo); err != nil {
-		return m.getServerVersionFallback(kubectlPath)
+		return m.getServerVersionFallback(ctx, kubectlPath, kubeContext)
 	}
 
 	if versionInfo.ServerVersion.GitVersion == "" {
@@ -288,8 +508,8 @@ func (m *Manager) getServerVersion(kubectlPath string) (string, error) {
 }
 
 // getServerVersionFallback tries to get version using short output
-func (m *Manager) getServerVersionFallback(kubectlPath string) (string, error) {
-	cmd := exec.Command(kubectlPath, "version", "--short")
+func (m *Manager) getServerVersionFallback(ctx context.Context, kubectlPath, kubeContext string) (string, error) {
+	cmd := exec.CommandContext(ctx, kubectlPath, contextArgs(kubeContext, "version", "--short")...)
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to execute kubectl version: %w", err)
@@ -309,6 +529,14 @@ func (m *Manager) getServerVersionFallback(kubectlPath string) (string, error) {
 	return "", fmt.Errorf("could not find server version in kubectl output")
 }
 
+// contextArgs returns the kubectl arguments, selecting a kubeconfig context when set
+func contextArgs(kubeContext string, args ...string) []string {
+	if kubeContext == "" {
+		return args
+	}
+	return append([]string{"--context", kubeContext}, args...)
+}
+
 // normalizeClusterVersion extracts the base kubectl version from cluster version
 // Examples:
 //
@@ -1,9 +1,17 @@
 package version
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/germainlefebvre4/kuve/pkg/config"
 )
@@ -172,6 +180,67 @@ func TestIsVersionInstalled(t *testing.T) {
 	}
 }
 
+func TestIsVersionPinned(t *testing.T) {
+	// Create a temporary directory
+	tmpDir, err := os.MkdirTemp("", "kuve-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	versionsDir := filepath.Join(tmpDir, "versions")
+
+	cfg := &config.Config{
+		HomeDir:        tmpDir,
+		KuveDir:        tmpDir,
+		BinDir:         filepath.Join(tmpDir, "bin"),
+		VersionsDir:    versionsDir,
+		CurrentSymlink: filepath.Join(tmpDir, "bin", "kubectl"),
+	}
+
+	manager := NewManager(cfg)
+
+	os.MkdirAll(filepath.Join(versionsDir, "v1.28.0"), 0755)
+	os.MkdirAll(filepath.Join(versionsDir, "v1.27.0"), 0755)
+	os.WriteFile(filepath.Join(versionsDir, "v1.28.0", config.PinFileName), []byte{}, 0644)
+
+	if !manager.IsVersionPinned("v1.28.0") {
+		t.Errorf("Expected version v1.28.0 to be pinned")
+	}
+	if manager.IsVersionPinned("v1.27.0") {
+		t.Errorf("Expected version v1.27.0 to not be pinned")
+	}
+}
+
+func TestInstalledCompanions(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "kuve-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	versionsDir := filepath.Join(tmpDir, "versions")
+	cfg := &config.Config{
+		HomeDir:     tmpDir,
+		KuveDir:     tmpDir,
+		BinDir:      filepath.Join(tmpDir, "bin"),
+		VersionsDir: versionsDir,
+	}
+
+	manager := NewManager(cfg)
+
+	os.MkdirAll(filepath.Join(versionsDir, "v1.28.0"), 0755)
+	os.WriteFile(filepath.Join(versionsDir, "v1.28.0", config.KubeadmBinaryName), []byte{}, 0755)
+
+	companions := manager.InstalledCompanions("v1.28.0")
+	if len(companions) != 1 || companions[0] != config.KubeadmBinaryName {
+		t.Errorf("InstalledCompanions() = %v, want [%s]", companions, config.KubeadmBinaryName)
+	}
+	if companions := manager.InstalledCompanions("v1.27.0"); len(companions) != 0 {
+		t.Errorf("Expected no companions for a missing version, got %v", companions)
+	}
+}
+
 func TestFindKubectlBinary(t *testing.T) {
 	// Create a temporary directory
 	tmpDir, err := os.MkdirTemp("", "kuve-test-*")
@@ -266,3 +335,170 @@ func TestNormalizeClusterVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveVersion(t *testing.T) {
+	manager := NewManager(&config.Config{})
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "full version",
+			input: "v1.28.3",
+			want:  "v1.28.3",
+		},
+		{
+			name:  "full version without v prefix",
+			input: "1.28.3",
+			want:  "v1.28.3",
+		},
+		{
+			name:  "pre-release version",
+			input: "1.30.0-rc.1",
+			want:  "v1.30.0-rc.1",
+		},
+		{
+			name:    "empty version",
+			input:   "  ",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := manager.ResolveVersion(context.Background(), tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveVersion(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveVersion(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectClusterVersionTimeout(t *testing.T) {
+	// Create a temporary directory
+	tmpDir, err := os.MkdirTemp("", "kuve-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// Fake kubectl hanging like an unreachable cluster
+	kubectlPath := filepath.Join(tmpDir, "kubectl")
+	os.WriteFile(kubectlPath, []byte("#!/bin/sh\nexec sleep 10\n"), 0755)
+
+	cfg := &config.Config{
+		HomeDir:        tmpDir,
+		KuveDir:        tmpDir,
+		BinDir:         tmpDir,
+		VersionsDir:    filepath.Join(tmpDir, "versions"),
+		CurrentSymlink: kubectlPath,
+	}
+	cfg.Settings.Timeouts.Cluster = 100 * time.Millisecond
+
+	manager := NewManager(cfg)
+
+	start := time.Now()
+	_, err = manager.DetectClusterVersion(context.Background())
+	if err == nil {
+		t.Fatalf("Expected DetectClusterVersion() to fail")
+	}
+	if !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("DetectClusterVersion() error = %v, want timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("DetectClusterVersion() took %s, timeout not applied", elapsed)
+	}
+}
+
+func TestResolveVersionFromReleases(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/kubernetes-sigs/kustomize/releases" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `[
+			{"tag_name": "kustomize/v5.10.0", "prerelease": true},
+			{"tag_name": "kustomize/v5.4.1"},
+			{"tag_name": "kustomize/v5.9.0"},
+			{"tag_name": "kyaml/v0.17.1"},
+			{"tag_name": "kustomize/v5.4.3"}
+		]`)
+	}))
+	defer server.Close()
+
+	defer func(url string) { githubAPIURL = url }(githubAPIURL)
+	githubAPIURL = server.URL
+
+	base := &config.Config{Settings: config.DefaultSettings()}
+	base.Settings.ToolDefinitions = map[string]config.Tool{
+		"kustomize": {
+			VersionFile: ".kustomize-version",
+			GitHubRepo:  "kubernetes-sigs/kustomize",
+			TagPrefix:   "kustomize/",
+			URL:         "https://example.com/{{.Version}}",
+		},
+	}
+	cfg, err := base.ForTool("kustomize")
+	if err != nil {
+		t.Fatalf("ForTool() error = %v", err)
+	}
+	manager := NewManager(cfg)
+
+	versions, err := manager.ListRemoteVersions(context.Background())
+	if err != nil {
+		t.Fatalf("ListRemoteVersions() error = %v", err)
+	}
+	if want := []string{"v5.9.0", "v5.4.3", "v5.4.1"}; !reflect.DeepEqual(versions, want) {
+		t.Errorf("ListRemoteVersions() = %v, want %v", versions, want)
+	}
+
+	tests := map[string]string{
+		"stable": "v5.9.0",
+		"5.4":    "v5.4.3",
+		"v5.4.1": "v5.4.1",
+	}
+	for spec, want := range tests {
+		got, err := manager.ResolveVersion(context.Background(), spec)
+		if err != nil {
+			t.Errorf("ResolveVersion(%q) error = %v", spec, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("ResolveVersion(%q) = %q, want %q", spec, got, want)
+		}
+	}
+
+	if _, err := manager.ResolveVersion(context.Background(), "5.8"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("ResolveVersion() error = %v, want ErrNotFound for a minor without releases", err)
+	}
+
+	// An unreachable release source is a network failure
+	server.Close()
+	if _, err := manager.ResolveVersion(context.Background(), "stable"); !errors.Is(err, ErrNetwork) {
+		t.Errorf("ResolveVersion() error = %v, want ErrNetwork", err)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v1.28.3", "v1.28.3", 0},
+		{"v1.9.0", "v1.10.0", -1},
+		{"v1.28.10", "v1.28.9", 1},
+		{"1.28", "v1.28.0", 0},
+	}
+
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,344 @@
+package version
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/germainlefebvre4/kuve/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+// Project file formats, in the default order of precedence (see config.Settings.VersionFiles)
+const (
+	// FormatVersionFile is the version file of the tool (.kubernetes-version for kubectl)
+	FormatVersionFile = "version-file"
+
+	// FormatKuve is the .kuve.yaml project file
+	FormatKuve = "kuve"
+
+	// FormatToolVersions is the asdf .tool-versions file
+	FormatToolVersions = "tool-versions"
+
+	// FormatMise is the mise.toml (or .mise.toml) file
+	FormatMise = "mise"
+
+	// FormatChart is the kubeVersion constraint of a Helm Chart.yaml, only
+	// read when listed in the versionFiles setting
+	FormatChart = "chart"
+)
+
+// DefaultProjectFormats is the order in which the project files of a
+// directory are read when the configuration does not set one. The
+// user-defined extractors follow.
+var DefaultProjectFormats = []string{FormatVersionFile, FormatKuve, FormatToolVersions, FormatMise}
+
+// File names of the project file formats
+const (
+	KuveFileName         = ".kuve.yaml"
+	ToolVersionsFileName = ".tool-versions"
+)
+
+// miseFileNames are the mise configuration files, in mise's order of precedence
+var miseFileNames = []string{"mise.toml", ".mise.toml"}
+
+// ProjectVersion is the version of a tool declared by a project file
+type ProjectVersion struct {
+	// Format is the format of the file (e.g. FormatToolVersions)
+	Format string
+
+	// Path is the file declaring the version
+	Path string
+
+	// Spec is the version (e.g. 1.28 or v1.28.3), empty when only a constraint is set
+	Spec string
+
+	// Constraint is a version range the version must satisfy (.kuve.yaml)
+	Constraint string
+
+	// Companions are the companion binaries required by the project (.kuve.yaml)
+	Companions []string
+
+	// Contexts maps kubeconfig context names to the version used with them (.kuve.yaml)
+	Contexts map[string]string
+}
+
+// Dir returns the directory of the project file
+func (p *ProjectVersion) Dir() string {
+	return filepath.Dir(p.Path)
+}
+
+// projectReader reads the version of a tool declared by a project file of a
+// directory, and returns nil when the file does not exist or does not
+// declare the tool
+type projectReader func(dir string, tool config.Tool) (*ProjectVersion, error)
+
+// builtinReaders holds the reader of each built-in format
+var builtinReaders = map[string]projectReader{
+	FormatVersionFile:  readVersionFileProject,
+	FormatKuve:         readKuveFile,
+	FormatToolVersions: readToolVersions,
+	FormatMise:         readMiseFile,
+	FormatChart:        mustExtractor(chartExtractor),
+}
+
+// ProjectFiles reads the project files of directories in the order of the
+// versionFiles setting, with the built-in formats and the user-defined extractors
+type ProjectFiles struct {
+	formats []string
+	readers map[string]projectReader
+}
+
+// NewProjectFiles returns the project file readers configured by the settings
+func NewProjectFiles(settings config.Settings) (*ProjectFiles, error) {
+	p := &ProjectFiles{readers: map[string]projectReader{}}
+	for format, read := range builtinReaders {
+		p.readers[format] = read
+	}
+
+	var extractorNames []string
+	for _, e := range settings.Extractors {
+		if _, ok := p.readers[e.Name]; ok {
+			return nil, fmt.Errorf("extractor %q: name already used by another version file format", e.Name)
+		}
+		read, err := newExtractor(e)
+		if err != nil {
+			return nil, err
+		}
+		p.readers[e.Name] = read
+		extractorNames = append(extractorNames, e.Name)
+	}
+
+	p.formats = settings.VersionFiles
+	if len(p.formats) == 0 {
+		p.formats = append(append([]string{}, DefaultProjectFormats...), extractorNames...)
+	}
+	for _, format := range p.formats {
+		if _, ok := p.readers[format]; !ok {
+			supported := append(append([]string{}, DefaultProjectFormats...), FormatChart)
+			return nil, fmt.Errorf("unknown version file format %q (supported: %s and the extractor names)",
+				format, strings.Join(supported, ", "))
+		}
+	}
+	return p, nil
+}
+
+// Read returns the version of a tool declared by the project files of a
+// directory: the formats are read in order and the first file declaring the
+// tool wins. It returns nil when no project file of the directory declares it.
+func (p *ProjectFiles) Read(dir string, tool config.Tool) (*ProjectVersion, error) {
+	return p.read(dir, tool, nil)
+}
+
+// read is Read ignoring the project files for which skip returns true, so
+// that the next format of the directory applies instead
+func (p *ProjectFiles) read(dir string, tool config.Tool, skip func(path string) bool) (*ProjectVersion, error) {
+	for _, format := range p.formats {
+		project, err := p.readers[format](dir, tool)
+		if err != nil {
+			return nil, err
+		}
+		if project != nil && (skip == nil || !skip(project.Path)) {
+			project.Format = format
+			return project, nil
+		}
+	}
+	return nil, nil
+}
+
+// Locate searches the project files of the current and parent directories
+// for the version of a tool. The nearest directory declaring the tool takes
+// precedence over the order of the formats. It returns nil when no project
+// file declares the tool.
+func (p *ProjectFiles) Locate(tool config.Tool) (*ProjectVersion, error) {
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		project, err := p.Read(currentDir, tool)
+		if err != nil || project != nil {
+			return project, err
+		}
+
+		parentDir := filepath.Dir(currentDir)
+		if parentDir == currentDir {
+			// Reached root directory
+			return nil, nil
+		}
+		currentDir = parentDir
+	}
+}
+
+// readVersionFileProject reads the version file of the tool
+func readVersionFileProject(dir string, tool config.Tool) (*ProjectVersion, error) {
+	spec, err := ReadVersionFileNamed(dir, tool.VersionFile)
+	if err != nil || spec == "" {
+		return nil, err
+	}
+	return &ProjectVersion{Path: filepath.Join(dir, tool.VersionFile), Spec: spec}, nil
+}
+
+// KuveFile is the content of a .kuve.yaml file. The top-level settings apply
+// to kubectl, and Tools holds the settings of other tools keyed by name.
+//
+//	version: 1.28.3
+//	constraint: ">=1.27 <1.30"
+//	companions: [kubectl-convert]
+//	contexts:
+//	  legacy-cluster: 1.26.15
+//	tools:
+//	  helm:
+//	    version: 3.14.0
+type KuveFile struct {
+	KuveToolSettings `yaml:",inline"`
+
+	Tools map[string]KuveToolSettings `yaml:"tools"`
+}
+
+// KuveToolSettings holds the settings of a tool in a .kuve.yaml file
+type KuveToolSettings struct {
+	Version    string            `yaml:"version"`
+	Constraint string            `yaml:"constraint"`
+	Companions []string          `yaml:"companions"`
+	Contexts   map[string]string `yaml:"contexts"`
+}
+
+// readKuveFile reads the settings of the tool in the .kuve.yaml file
+func readKuveFile(dir string, tool config.Tool) (*ProjectVersion, error) {
+	path := filepath.Join(dir, KuveFileName)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var file KuveFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	settings, ok := file.Tools[tool.Name]
+	if !ok && tool.IsKubectl() {
+		settings = file.KuveToolSettings
+	}
+	if settings.Version == "" && settings.Constraint == "" {
+		return nil, nil
+	}
+
+	if settings.Constraint != "" {
+		if _, err := ParseConstraint(settings.Constraint); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	return &ProjectVersion{
+		Path:       path,
+		Spec:       strings.TrimSpace(settings.Version),
+		Constraint: settings.Constraint,
+		Companions: settings.Companions,
+		Contexts:   settings.Contexts,
+	}, nil
+}
+
+// readToolVersions reads the version of the tool in an asdf .tool-versions
+// file ("kubectl 1.28.3" lines). The first version of the line is used, and
+// "system" is ignored since kuve does not manage it.
+func readToolVersions(dir string, tool config.Tool) (*ProjectVersion, error) {
+	path := filepath.Join(dir, ToolVersionsFileName)
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != tool.Name {
+			continue
+		}
+		if fields[1] == "system" {
+			return nil, nil
+		}
+		return &ProjectVersion{Path: path, Spec: fields[1]}, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return nil, nil
+}
+
+var (
+	// tomlTableRegex matches a TOML table header ([tools])
+	tomlTableRegex = regexp.MustCompile(`^\[\s*([^\]]+?)\s*\]`)
+
+	// tomlStringRegex matches the first string of a TOML value
+	tomlStringRegex = regexp.MustCompile(`"([^"]*)"|'([^']*)'`)
+
+	// tomlVersionKeyRegex matches the version key of an inline table
+	tomlVersionKeyRegex = regexp.MustCompile(`\bversion\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+)
+
+// readMiseFile reads the version of the tool in the [tools] table of a mise
+// configuration file. The value may be a string ("1.28.3"), an array whose
+// first entry is used, or an inline table with a version key. Only the
+// subset of TOML used by these entries is supported.
+func readMiseFile(dir string, tool config.Tool) (*ProjectVersion, error) {
+	for _, name := range miseFileNames {
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		if spec := miseToolVersion(string(data), tool.Name); spec != "" {
+			return &ProjectVersion{Path: path, Spec: spec}, nil
+		}
+	}
+	return nil, nil
+}
+
+// miseToolVersion returns the version of a tool in the [tools] table of a
+// mise configuration, empty when the tool is not listed
+func miseToolVersion(data, toolName string) string {
+	inTools := false
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if matches := tomlTableRegex.FindStringSubmatch(line); matches != nil {
+			inTools = matches[1] == "tools"
+			continue
+		}
+		if !inTools || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.Trim(strings.TrimSpace(key), `"'`) != toolName {
+			continue
+		}
+
+		matches := tomlVersionKeyRegex.FindStringSubmatch(value)
+		if matches == nil {
+			matches = tomlStringRegex.FindStringSubmatch(value)
+		}
+		if matches == nil {
+			return ""
+		}
+		return matches[1] + matches[2]
+	}
+	return ""
+}
@@ -0,0 +1,238 @@
+package version
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/germainlefebvre4/kuve/pkg/config"
+)
+
+func TestProjectReaders(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "kuve-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		read    projectReader
+		tool    string
+		want    string
+	}{
+		{name: "tool-versions", file: ToolVersionsFileName, content: "# tools\nhelm 3.14.0\nkubectl 1.28.3 1.27.0 # comment\n", read: readToolVersions, tool: "kubectl", want: "1.28.3"},
+		{name: "tool-versions without the tool", file: ToolVersionsFileName, content: "helm 3.14.0\n", read: readToolVersions, tool: "kubectl"},
+		{name: "tool-versions system", file: ToolVersionsFileName, content: "kubectl system\n", read: readToolVersions, tool: "kubectl"},
+		{name: "mise string", file: "mise.toml", content: "[env]\nkubectl = \"no\"\n\n[tools]\nnode = \"20\"\nkubectl = \"1.28.3\" # pinned\n", read: readMiseFile, tool: "kubectl", want: "1.28.3"},
+		{name: "mise array", file: "mise.toml", content: "[tools]\nkubectl = ['1.29.1', '1.28']\n", read: readMiseFile, tool: "kubectl", want: "1.29.1"},
+		{name: "mise inline table", file: ".mise.toml", content: "[tools]\n\"helm\" = { version = \"3.14.0\", os = [\"linux\"] }\n", read: readMiseFile, tool: "helm", want: "3.14.0"},
+		{name: "mise other table", file: "mise.toml", content: "[settings]\nkubectl = \"1.28.3\"\n", read: readMiseFile, tool: "kubectl"},
+		{name: "kuve kubectl", file: KuveFileName, content: "version: 1.28.3\ntools:\n  helm:\n    version: 3.14.0\n", read: readKuveFile, tool: "kubectl", want: "1.28.3"},
+		{name: "kuve other tool", file: KuveFileName, content: "version: 1.28.3\ntools:\n  helm:\n    version: 3.14.0\n", read: readKuveFile, tool: "helm", want: "3.14.0"},
+		{name: "kuve tool not listed", file: KuveFileName, content: "version: 1.28.3\n", read: readKuveFile, tool: "helm"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(tmpDir, tt.name)
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				t.Fatalf("Failed to create directory: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, tt.file), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write %s: %v", tt.file, err)
+			}
+
+			project, err := tt.read(dir, config.Tool{Name: tt.tool})
+			if err != nil {
+				t.Fatalf("read() error = %v", err)
+			}
+			var got string
+			if project != nil {
+				got = project.Spec
+				if project.Path != filepath.Join(dir, tt.file) {
+					t.Errorf("Path = %q, want %q", project.Path, filepath.Join(dir, tt.file))
+				}
+			}
+			if got != tt.want {
+				t.Errorf("Spec = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// Missing files are not errors
+	if project, err := readMiseFile(tmpDir, config.KubectlTool()); project != nil || err != nil {
+		t.Errorf("readMiseFile() = %+v, %v; want nil, nil", project, err)
+	}
+}
+
+func TestSelectVersionProjectFiles(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "kuve-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	subDir := filepath.Join(tmpDir, "service")
+	if err := os.MkdirAll(subDir, 0755); err != nil {
+		t.Fatalf("Failed to create directories: %v", err)
+	}
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+	write(filepath.Join(tmpDir, ToolVersionsFileName), "kubectl 1.27.16\n")
+	write(filepath.Join(subDir, "mise.toml"), "[tools]\nkubectl = \"1.28.3\"\n")
+	write(filepath.Join(subDir, KuveFileName), "constraint: \">=1.29 <1.31\"\ncompanions: [kubeadm]\ncontexts:\n  legacy: 1.26.15\n")
+
+	kubeconfig := filepath.Join(tmpDir, "kubeconfig")
+	write(kubeconfig, "apiVersion: v1\ncurrent-context: prod\n")
+	t.Setenv("KUBECONFIG", kubeconfig)
+	t.Setenv("KUVE_KUBECTL_VERSION", "")
+	t.Chdir(subDir)
+
+	cfg := &config.Config{}
+
+	// .kuve.yaml precedes mise.toml by default
+	selection, err := SelectVersion(cfg)
+	if err != nil {
+		t.Fatalf("SelectVersion() error = %v", err)
+	}
+	if selection.Format != FormatKuve || selection.Constraint != ">=1.29 <1.31" || selection.Spec != "" ||
+		!reflect.DeepEqual(selection.Companions, []string{"kubeadm"}) || selection.Dir != subDir {
+		t.Errorf("SelectVersion() = %+v, want the .kuve.yaml constraint", selection)
+	}
+
+	// The configured order decides between the files of a directory
+	cfg.Settings.VersionFiles = []string{FormatMise, FormatKuve}
+	if selection, err = SelectVersion(cfg); err != nil || selection.Format != FormatMise || selection.Spec != "1.28.3" {
+		t.Errorf("SelectVersion() = %+v, %v; want mise.toml first", selection, err)
+	}
+
+	// Formats left out are ignored, the nearest directory wins over the order
+	cfg.Settings.VersionFiles = []string{FormatToolVersions}
+	if selection, err = SelectVersion(cfg); err != nil || selection.Spec != "1.27.16" || selection.Dir != tmpDir {
+		t.Errorf("SelectVersion() = %+v, %v; want .tool-versions of the parent", selection, err)
+	}
+
+	cfg.Settings.VersionFiles = []string{"asdf"}
+	if _, err := SelectVersion(cfg); err == nil {
+		t.Errorf("SelectVersion() with an unknown format succeeded")
+	}
+
+	// Context overrides apply to the current kubeconfig context
+	cfg.Settings.VersionFiles = nil
+	write(kubeconfig, "apiVersion: v1\ncurrent-context: legacy\n")
+	selection, err = SelectVersion(cfg)
+	if err != nil || selection.Source != SourceContext || selection.Spec != "1.26.15" || selection.Context != "legacy" || selection.Constraint != "" {
+		t.Errorf("SelectVersion() = %+v, %v; want the legacy context override", selection, err)
+	}
+}
+
+func TestResolveSelection(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "kuve-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"tag_name": "v1.30.2"}, {"tag_name": "v1.29.7"}, {"tag_name": "v1.29.6"}, {"tag_name": "v1.28.12"}]`)
+	}))
+	defer server.Close()
+
+	defer func(url string) { githubAPIURL = url }(githubAPIURL)
+	githubAPIURL = server.URL
+
+	cfg := &config.Config{VersionsDir: filepath.Join(tmpDir, "versions"), Settings: config.DefaultSettings()}
+	manager := NewManager(cfg)
+	ctx := context.Background()
+
+	// Latest release without a matching installed version
+	selection := &Selection{Constraint: ">=1.27.0-0 <1.30.0-0", Origin: KuveFileName}
+	if got, err := manager.ResolveSelection(ctx, selection); err != nil || got != "v1.29.7" {
+		t.Errorf("ResolveSelection() = %q, %v; want v1.29.7", got, err)
+	}
+
+	// Installed versions are preferred
+	if err := os.MkdirAll(filepath.Join(cfg.VersionsDir, "v1.28.3"), 0755); err != nil {
+		t.Fatalf("Failed to create version directory: %v", err)
+	}
+	if got, err := manager.ResolveSelection(ctx, selection); err != nil || got != "v1.28.3" {
+		t.Errorf("ResolveSelection() = %q, %v; want the installed v1.28.3", got, err)
+	}
+
+	selection = &Selection{Spec: "1.30.1", Constraint: "<1.30", Origin: KuveFileName}
+	if _, err := manager.ResolveSelection(ctx, selection); err == nil {
+		t.Errorf("ResolveSelection() accepted a version outside its constraint")
+	}
+
+	selection = &Selection{Constraint: ">=1.31"}
+	if _, err := manager.ResolveSelection(ctx, selection); !errors.Is(err, ErrNotFound) {
+		t.Errorf("ResolveSelection() error = %v, want ErrNotFound", err)
+	}
+
+	for _, spec := range []string{"../../bin", "1.28.3-dirty"} {
+		selection = &Selection{Spec: spec, Origin: ToolVersionsFileName}
+		if got, err := manager.ResolveSelection(ctx, selection); err == nil {
+			t.Errorf("ResolveSelection(%q) = %s, want an invalid version error", spec, got)
+		}
+	}
+}
+
+func TestResolveSelectionSpecs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"tag_name": "v5.9.0"}, {"tag_name": "v5.4.3"}, {"tag_name": "v5.4.1"}]`)
+	}))
+	defer server.Close()
+
+	defer func(url string) { githubAPIURL = url }(githubAPIURL)
+	githubAPIURL = server.URL
+
+	base := &config.Config{Settings: config.DefaultSettings()}
+	base.Settings.ToolDefinitions = map[string]config.Tool{
+		"kustomize": {
+			VersionFile: ".kustomize-version",
+			GitHubRepo:  "kubernetes-sigs/kustomize",
+			URL:         "https://example.com/{{.Version}}",
+		},
+	}
+	cfg, err := base.ForTool("kustomize")
+	if err != nil {
+		t.Fatalf("ForTool() error = %v", err)
+	}
+	manager := NewManager(cfg)
+
+	// Minor versions and latest, as written in .tool-versions and mise.toml
+	tests := []struct {
+		spec       string
+		constraint string
+		want       string
+	}{
+		{spec: "5.4", want: "v5.4.3"},
+		{spec: "latest", want: "v5.9.0"},
+		{spec: "5.4.1", want: "v5.4.1"},
+		{spec: "5.4", constraint: "~5.4", want: "v5.4.3"},
+	}
+	for _, tt := range tests {
+		selection := &Selection{Spec: tt.spec, Constraint: tt.constraint, Origin: ToolVersionsFileName}
+		if got, err := manager.ResolveSelection(context.Background(), selection); err != nil || got != tt.want {
+			t.Errorf("ResolveSelection(%q) = %q, %v; want %s", tt.spec, got, err, tt.want)
+		}
+	}
+
+	selection := &Selection{Spec: "latest", Constraint: "<5.9", Origin: KuveFileName}
+	if _, err := manager.ResolveSelection(context.Background(), selection); err == nil {
+		t.Errorf("ResolveSelection() accepted a latest version outside its constraint")
+	}
+}
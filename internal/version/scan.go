@@ -0,0 +1,62 @@
+package version
+
+import (
+	"io/fs"
+	"path/filepath"
+
+	"github.com/germainlefebvre4/kuve/internal/gitignore"
+	"github.com/germainlefebvre4/kuve/pkg/config"
+)
+
+// ScanTree walks a directory tree and returns the version of a tool declared
+// by the project files of each directory, in walk order. Directories and
+// files ignored by the .gitignore files of the tree are skipped, as well as
+// .git directories; a directory whose first project file is ignored uses its
+// next project file.
+func ScanTree(root string, files *ProjectFiles, tool config.Tool) ([]*ProjectVersion, error) {
+	var ignore gitignore.Matcher
+	var projects []*ProjectVersion
+
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == "." {
+			rel = ""
+		}
+
+		if rel != "" && (entry.Name() == ".git" || ignore.Match(rel, true)) {
+			return filepath.SkipDir
+		}
+		if err := ignore.AddFile(root, rel); err != nil {
+			return err
+		}
+
+		// Ignored project files (e.g. a local mise.toml) give way to the
+		// next project file of the directory
+		project, err := files.read(path, tool, func(file string) bool {
+			rel, err := filepath.Rel(root, file)
+			return err == nil && ignore.Match(filepath.ToSlash(rel), false)
+		})
+		if err != nil {
+			return err
+		}
+		if project != nil {
+			projects = append(projects, project)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
@@ -0,0 +1,76 @@
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/germainlefebvre4/kuve/pkg/config"
+)
+
+func TestScanTree(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "kuve-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	files := map[string]string{
+		".gitignore":                                   "vendor/\n",
+		config.VersionFileName:                         "v1.30.1\n",
+		"services/api/" + config.VersionFileName:       "v1.28.3\n",
+		"services/web/" + ToolVersionsFileName:         "kubectl 1.29.0\n",
+		"services/web/.gitignore":                      "/cache\n",
+		"services/web/cache/" + config.VersionFileName: "v1.20.0\n",
+		"services/jobs/" + KuveFileName:                "constraint: \">=1.27\"\n",
+		"services/ops/.gitignore":                      config.VersionFileName + "\n",
+		"services/ops/" + config.VersionFileName:       "v1.19.0\n",
+		"services/ops/" + ToolVersionsFileName:         "kubectl 1.27.4\n",
+		"services/tmp/.gitignore":                      "/" + config.VersionFileName + "\n",
+		"services/tmp/" + config.VersionFileName:       "v1.18.0\n",
+		"vendor/lib/" + config.VersionFileName:         "v1.21.0\n",
+		".git/modules/" + config.VersionFileName:       "v1.22.0\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	projectFiles, err := NewProjectFiles(config.Settings{})
+	if err != nil {
+		t.Fatalf("NewProjectFiles() error = %v", err)
+	}
+	projects, err := ScanTree(tmpDir, projectFiles, config.KubectlTool())
+	if err != nil {
+		t.Fatalf("ScanTree() error = %v", err)
+	}
+
+	want := map[string]string{
+		"":              "v1.30.1",
+		"services/api":  "v1.28.3",
+		"services/jobs": ">=1.27",
+		"services/ops":  "1.27.4",
+		"services/web":  "1.29.0",
+	}
+	if len(projects) != len(want) {
+		t.Fatalf("ScanTree() found %d projects, want %d: %+v", len(projects), len(want), projects)
+	}
+	for _, project := range projects {
+		rel, _ := filepath.Rel(tmpDir, project.Dir())
+		if rel == "." {
+			rel = ""
+		}
+		got := project.Spec
+		if got == "" {
+			got = project.Constraint
+		}
+		if got != want[filepath.ToSlash(rel)] {
+			t.Errorf("ScanTree() found %q in %q, want %q", got, rel, want[filepath.ToSlash(rel)])
+		}
+	}
+}
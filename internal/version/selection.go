@@ -0,0 +1,196 @@
+package version
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/germainlefebvre4/kuve/pkg/config"
+)
+
+// Sources of the version selected for a directory
+const (
+	// SourceEnv is the environment variable of the tool (e.g. KUVE_KUBECTL_VERSION)
+	SourceEnv = "env"
+
+	// SourceFile is a project file (e.g. .kubernetes-version, .tool-versions)
+	// in the directory or one of its parents
+	SourceFile = "file"
+
+	// SourceContext is the override of the current kubeconfig context in a
+	// .kuve.yaml project file
+	SourceContext = "context"
+
+	// SourceGlobal is the global version set with 'kuve global'
+	SourceGlobal = "global"
+)
+
+// Selection is the version of a tool that applies to a directory and where it comes from
+type Selection struct {
+	// Spec is the version as written in its source (e.g. 1.28 or v1.28.3),
+	// empty when only Constraint is set
+	Spec string
+
+	// Constraint is the version range declared by a .kuve.yaml project file
+	Constraint string
+
+	// Companions are the companion binaries required by the project file
+	Companions []string
+
+	// Source is one of SourceEnv, SourceFile, SourceContext or SourceGlobal
+	Source string
+
+	// Origin is the environment variable, project file or configuration file
+	// holding the version
+	Origin string
+
+	// Format is the format of the project file (e.g. FormatToolVersions),
+	// with SourceFile and SourceContext
+	Format string
+
+	// Dir is the directory of the project file, with SourceFile and SourceContext
+	Dir string
+
+	// Context is the kubeconfig context, with SourceContext
+	Context string
+}
+
+// SelectVersion returns the version of the managed tool that applies to the
+// current directory: the environment override, else the nearest project file
+// (read in the order of the versionFiles setting), else the global version.
+// The per-context overrides of a .kuve.yaml file apply when the current
+// kubeconfig context is listed. ErrNoVersionFile is returned when none applies.
+func SelectVersion(cfg *config.Config) (*Selection, error) {
+	tool := cfg.Tool()
+
+	if spec := strings.TrimSpace(os.Getenv(tool.VersionEnv())); spec != "" {
+		return &Selection{Spec: spec, Source: SourceEnv, Origin: tool.VersionEnv()}, nil
+	}
+
+	projectFiles, err := NewProjectFiles(cfg.Settings)
+	if err != nil {
+		return nil, err
+	}
+	project, err := projectFiles.Locate(tool)
+	if err != nil {
+		return nil, fmt.Errorf("error searching for version file: %w", err)
+	}
+	if project != nil {
+		selection := &Selection{
+			Spec:       project.Spec,
+			Constraint: project.Constraint,
+			Companions: project.Companions,
+			Source:     SourceFile,
+			Origin:     project.Path,
+			Format:     project.Format,
+			Dir:        project.Dir(),
+		}
+
+		if len(project.Contexts) > 0 {
+			kubeContext, err := CurrentKubeContext()
+			if err != nil {
+				return nil, fmt.Errorf("failed to read the context overrides of %s: %w", project.Path, err)
+			}
+			if spec, ok := project.Contexts[kubeContext]; ok && kubeContext != "" {
+				// Context overrides are exceptions to the constraint of the project
+				selection.Spec = spec
+				selection.Constraint = ""
+				selection.Source = SourceContext
+				selection.Context = kubeContext
+			}
+		}
+		return selection, nil
+	}
+
+	if spec := cfg.GlobalVersion(); spec != "" {
+		return &Selection{Spec: spec, Source: SourceGlobal, Origin: cfg.ConfigFile}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s in current or parent directories, and no global %s version (see 'kuve global')",
+		ErrNoVersionFile, tool.VersionFile, tool.Name)
+}
+
+// Describe returns a human readable description of where the version comes from
+func (s *Selection) Describe() string {
+	switch s.Source {
+	case SourceEnv:
+		return "$" + s.Origin
+	case SourceGlobal:
+		return "global version in " + s.Origin
+	case SourceContext:
+		return fmt.Sprintf("context %s in %s", s.Context, s.Origin)
+	}
+	if s.Spec == "" {
+		return fmt.Sprintf("constraint %s in %s", s.Constraint, s.Origin)
+	}
+	return s.Origin
+}
+
+// ResolveSelection returns the exact version of a selection. A version is
+// resolved like 'kuve install' (1.28 to its latest patch, latest to the
+// latest stable release) and checked against the constraint of its project
+// file, while a constraint alone resolves to the latest version satisfying it.
+func (m *Manager) ResolveSelection(ctx context.Context, s *Selection) (string, error) {
+	if s.Spec == "" {
+		return m.ResolveConstraint(ctx, s.Constraint)
+	}
+
+	version, err := m.ResolveVersion(ctx, s.Spec)
+	if err != nil {
+		return "", err
+	}
+	// Project files are untrusted input naming the installed directory
+	if err := config.ValidateVersion(version); err != nil {
+		return "", fmt.Errorf("%s: %w", s.Origin, err)
+	}
+
+	if s.Constraint != "" {
+		constraint, err := ParseConstraint(s.Constraint)
+		if err != nil {
+			return "", err
+		}
+		if !constraint.Check(version) {
+			return "", fmt.Errorf("version %s of %s does not satisfy its constraint %s", version, s.Origin, s.Constraint)
+		}
+	}
+	return version, nil
+}
+
+// ResolveConstraint returns the latest version satisfying a constraint. The
+// installed versions are preferred, so that no network access is needed once
+// a matching version is installed; the latest matching release is used otherwise.
+func (m *Manager) ResolveConstraint(ctx context.Context, s string) (string, error) {
+	constraint, err := ParseConstraint(s)
+	if err != nil {
+		return "", err
+	}
+
+	installed, err := m.ListInstalledVersions()
+	if err != nil {
+		return "", err
+	}
+	if version := latestMatching(installed, constraint); version != "" {
+		return version, nil
+	}
+
+	releases, err := m.listReleases(ctx)
+	if err != nil {
+		return "", err
+	}
+	if version := latestMatching(releases, constraint); version != "" {
+		return version, nil
+	}
+	return "", fmt.Errorf("%w: no %s release satisfies %s", ErrNotFound, m.config.Tool().Name, s)
+}
+
+// latestMatching returns the latest of the versions satisfying the constraint
+func latestMatching(versions []string, constraint *Constraint) string {
+	var latest string
+	for _, version := range versions {
+		if constraint.Check(version) && (latest == "" || CompareVersions(version, latest) > 0) {
+			latest = version
+		}
+	}
+	return latest
+}
@@ -0,0 +1,54 @@
+package version
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/germainlefebvre4/kuve/pkg/config"
+)
+
+func TestSelectVersion(t *testing.T) {
+	// Create a temporary directory
+	tmpDir, err := os.MkdirTemp("", "kuve-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	projectDir := filepath.Join(tmpDir, "project")
+	subDir := filepath.Join(projectDir, "sub")
+	if err := os.MkdirAll(subDir, 0755); err != nil {
+		t.Fatalf("Failed to create directories: %v", err)
+	}
+	t.Chdir(tmpDir)
+	t.Setenv("KUVE_KUBECTL_VERSION", "")
+
+	cfg := &config.Config{ConfigFile: filepath.Join(tmpDir, "config.yaml")}
+	if _, err := SelectVersion(cfg); !errors.Is(err, ErrNoVersionFile) {
+		t.Fatalf("SelectVersion() error = %v, want ErrNoVersionFile", err)
+	}
+
+	cfg.Settings.GlobalVersions = map[string]string{"kubectl": "v1.27.16"}
+	selection, err := SelectVersion(cfg)
+	if err != nil || selection.Source != SourceGlobal || selection.Spec != "v1.27.16" {
+		t.Fatalf("SelectVersion() = %+v, %v; want the global version", selection, err)
+	}
+
+	versionFile := filepath.Join(projectDir, config.VersionFileName)
+	if err := os.WriteFile(versionFile, []byte("v1.28.3\n"), 0644); err != nil {
+		t.Fatalf("Failed to write version file: %v", err)
+	}
+	t.Chdir(subDir)
+	selection, err = SelectVersion(cfg)
+	if err != nil || selection.Source != SourceFile || selection.Spec != "v1.28.3" || selection.Dir != projectDir {
+		t.Fatalf("SelectVersion() = %+v, %v; want the version file of the parent", selection, err)
+	}
+
+	t.Setenv("KUVE_KUBECTL_VERSION", "1.30")
+	selection, err = SelectVersion(cfg)
+	if err != nil || selection.Source != SourceEnv || selection.Spec != "1.30" || selection.Origin != "KUVE_KUBECTL_VERSION" {
+		t.Fatalf("SelectVersion() = %+v, %v; want the environment override", selection, err)
+	}
+}
@@ -0,0 +1,57 @@
+package version
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxKubectlSkew is the number of minor versions kubectl may be ahead of or
+// behind the API server, as per the Kubernetes version skew policy
+const MaxKubectlSkew = 1
+
+// SupportedMinorReleases is the number of minor releases maintained by the
+// Kubernetes project; older minor releases are end of life
+const SupportedMinorReleases = 3
+
+// IsEndOfLife reports whether the minor release of a version is no longer
+// maintained, given the latest stable version
+func IsEndOfLife(version, latest string) (bool, error) {
+	skew, err := MinorSkew(version, latest)
+	if err != nil {
+		return false, err
+	}
+	return skew <= -SupportedMinorReleases, nil
+}
+
+// MinorSkew returns the number of minor versions client is ahead of server,
+// negative when it is behind. Both versions must share the same major version.
+func MinorSkew(client, server string) (int, error) {
+	clientMajor, clientMinor, err := majorMinor(client)
+	if err != nil {
+		return 0, err
+	}
+	serverMajor, serverMinor, err := majorMinor(server)
+	if err != nil {
+		return 0, err
+	}
+	if clientMajor != serverMajor {
+		return 0, fmt.Errorf("major versions of %s and %s differ", client, server)
+	}
+	return clientMinor - serverMinor, nil
+}
+
+// majorMinor parses the major and minor numbers of a vMAJOR.MINOR[.PATCH] version
+func majorMinor(version string) (major, minor int, err error) {
+	parts := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 3)
+	if len(parts) < 2 {
+		return 0, 0, fmt.Errorf("invalid version %q", version)
+	}
+	if major, err = strconv.Atoi(parts[0]); err != nil {
+		return 0, 0, fmt.Errorf("invalid version %q", version)
+	}
+	if minor, err = strconv.Atoi(parts[1]); err != nil {
+		return 0, 0, fmt.Errorf("invalid version %q", version)
+	}
+	return major, minor, nil
+}
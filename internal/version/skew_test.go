@@ -0,0 +1,49 @@
+package version
+
+import "testing"
+
+func TestMinorSkew(t *testing.T) {
+	tests := []struct {
+		client  string
+		server  string
+		want    int
+		wantErr bool
+	}{
+		{client: "v1.28.3", server: "v1.28.0", want: 0},
+		{client: "v1.29.0", server: "v1.28.15", want: 1},
+		{client: "v1.26.1", server: "v1.28.2", want: -2},
+		{client: "1.30", server: "v1.28.2", want: 2},
+		{client: "v2.0.0", server: "v1.28.2", wantErr: true},
+		{client: "latest", server: "v1.28.2", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := MinorSkew(tt.client, tt.server)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("MinorSkew(%q, %q) error = %v, wantErr %v", tt.client, tt.server, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("MinorSkew(%q, %q) = %d, want %d", tt.client, tt.server, got, tt.want)
+		}
+	}
+}
+
+func TestIsEndOfLife(t *testing.T) {
+	tests := map[string]bool{
+		"v1.31.2":  false,
+		"v1.29.0":  false,
+		"v1.28.15": true,
+		"v1.20.0":  true,
+	}
+
+	for v, want := range tests {
+		got, err := IsEndOfLife(v, "v1.31.4")
+		if err != nil {
+			t.Fatalf("IsEndOfLife(%q) error = %v", v, err)
+		}
+		if got != want {
+			t.Errorf("IsEndOfLife(%q) = %v, want %v", v, got, want)
+		}
+	}
+}
@@ -1,8 +1,12 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 const (
@@ -14,15 +18,229 @@ const (
 
 	// KubectlBinaryName is the name of the kubectl binary
 	KubectlBinaryName = "kubectl"
+
+	// KubectlConvertBinaryName is the name of the kubectl-convert plugin binary
+	KubectlConvertBinaryName = "kubectl-convert"
+
+	// KubeadmBinaryName is the name of the kubeadm binary
+	KubeadmBinaryName = "kubeadm"
+
+	// LockFileName is the name of the lock file written next to version files
+	LockFileName = ".kuve.lock"
+
+	// MetadataFileName is the file recording the checksums of an installed version
+	MetadataFileName = ".install.json"
+
+	// HistoryFileName is the log of version switches in the kuve directory
+	HistoryFileName = "history.log"
+
+	// PinFileName is the marker file that protects a version from removal
+	PinFileName = ".pinned"
+
+	// ConfigFileName is the name of the user configuration file
+	ConfigFileName = "config.yaml"
+
+	// ConfigFileEnv overrides the location of the user configuration file
+	ConfigFileEnv = "KUVE_CONFIG"
+
+	// KubernetesReleaseSigner is the identity signing the Kubernetes release artifacts
+	KubernetesReleaseSigner = "krel-trust@k8s-releng-prod.iam.gserviceaccount.com"
+
+	// KubernetesReleaseSignerIssuer is the OIDC issuer of the Kubernetes release signer
+	KubernetesReleaseSignerIssuer = "https://accounts.google.com"
+
+	// MirrorFormatBinary is a mirror hosting bare binaries (<url>/<version>/bin/<os>/<arch>/kubectl)
+	MirrorFormatBinary = "binary"
+
+	// MirrorFormatTarball is a mirror hosting client tarballs (<url>/<version>/kubernetes-client-<os>-<arch>.tar.gz)
+	MirrorFormatTarball = "tarball"
 )
 
+// CompanionBinaryNames lists the binaries published alongside kubectl under the
+// same release path that can be installed next to it in a version directory
+var CompanionBinaryNames = []string{KubectlConvertBinaryName, KubeadmBinaryName}
+
 // Config holds the application configuration
 type Config struct {
 	HomeDir        string
 	KuveDir        string
 	BinDir         string
 	VersionsDir    string
+	PlatformsDir   string
 	CurrentSymlink string
+	ConfigFile     string
+
+	// Settings holds the user settings read from ConfigFile
+	Settings Settings
+
+	// tool is the managed tool, kubectl when nil (see ForTool)
+	tool *Tool
+}
+
+// Settings holds the user-editable configuration
+type Settings struct {
+	Download  DownloadSettings  `yaml:"download"`
+	Timeouts  TimeoutSettings   `yaml:"timeouts"`
+	HTTP      HTTPSettings      `yaml:"http"`
+	Signature SignatureSettings `yaml:"signature"`
+
+	// ToolDefinitions declares the tools managed in addition to kubectl, keyed by name
+	ToolDefinitions map[string]Tool `yaml:"tools"`
+
+	// VersionFiles is the order in which the project files of a directory are
+	// read: "version-file", "kuve", "tool-versions", "mise", "chart" and the
+	// names of the Extractors. Formats left out are ignored. When empty, the
+	// formats other than "chart" are read in this order, then the Extractors.
+	VersionFiles []string `yaml:"versionFiles"`
+
+	// Extractors read a version or a version constraint from other project files
+	Extractors []Extractor `yaml:"extractors"`
+
+	// GlobalVersions holds the default version of each tool, keyed by tool
+	// name, used where no version file applies (see 'kuve global')
+	GlobalVersions map[string]string `yaml:"global"`
+}
+
+// DownloadSettings controls how kubectl binaries are downloaded
+type DownloadSettings struct {
+	// Retries is the number of retries after a failed download attempt
+	Retries int `yaml:"retries"`
+
+	// RetryBackoff is the delay before the first retry, doubled on each retry
+	RetryBackoff time.Duration `yaml:"retryBackoff"`
+
+	// MaxRetryBackoff caps the delay between two retries
+	MaxRetryBackoff time.Duration `yaml:"maxRetryBackoff"`
+
+	// Timeout is the time limit of a single download attempt (0 means no limit)
+	Timeout time.Duration `yaml:"timeout"`
+
+	// Mirrors are tried in order; dl.k8s.io is used when empty
+	Mirrors []Mirror `yaml:"mirrors"`
+}
+
+// Mirror is a location kubectl releases are downloaded from
+type Mirror struct {
+	// URL is the base URL of the releases (e.g. https://dl.k8s.io/release)
+	URL string `yaml:"url"`
+
+	// Format is the artifact format: "binary" (default) or "tarball"
+	Format string `yaml:"format"`
+
+	// SkipChecksum disables the verification against the published .sha256 files
+	SkipChecksum bool `yaml:"skipChecksum"`
+}
+
+// TimeoutSettings bounds the time spent on network and cluster calls
+type TimeoutSettings struct {
+	// HTTP is the time limit of release metadata requests (0 means no limit)
+	HTTP time.Duration `yaml:"http"`
+
+	// Cluster is the time limit of cluster version detection (0 means no limit)
+	Cluster time.Duration `yaml:"cluster"`
+}
+
+// HTTPSettings configures TLS and authentication of outgoing HTTP requests.
+// Proxies are configured with the HTTPS_PROXY, HTTP_PROXY and NO_PROXY variables.
+type HTTPSettings struct {
+	// CABundle is a PEM file of certificates trusted in addition to the system roots
+	CABundle string `yaml:"caBundle"`
+
+	// ClientCert and ClientKey are the PEM files used for mutual TLS
+	ClientCert string `yaml:"clientCert"`
+	ClientKey  string `yaml:"clientKey"`
+
+	// Netrc is the path of the .netrc file (defaults to $NETRC or ~/.netrc)
+	Netrc string `yaml:"netrc"`
+
+	// Auth holds bearer token credentials keyed by host name
+	Auth map[string]HostAuth `yaml:"auth"`
+}
+
+// SignatureSettings controls the verification of the cosign keyless
+// signatures (.sig/.cert) published next to kubectl release artifacts
+type SignatureSettings struct {
+	// Verify requires a valid signature before installing a binary
+	Verify bool `yaml:"verify"`
+
+	// Identity is the expected subject (e-mail or URI) of the signing certificate
+	Identity string `yaml:"identity"`
+
+	// Issuer is the expected OIDC issuer of the signing certificate
+	Issuer string `yaml:"issuer"`
+
+	// Roots is a PEM file of trusted root and intermediate certificates
+	// replacing the built-in Sigstore public-good roots
+	Roots string `yaml:"roots"`
+}
+
+// Extractor reads the version of a tool from a project file, with either a
+// regular expression or a JSONPath expression
+type Extractor struct {
+	// Name identifies the extractor in VersionFiles
+	Name string `yaml:"name"`
+
+	// Tool is the tool the file declares the version of (defaults to kubectl)
+	Tool string `yaml:"tool"`
+
+	// File is the name of the file in the project directories (e.g. Chart.yaml)
+	File string `yaml:"file"`
+
+	// Regex is matched against the file content; the first capture group, or
+	// the whole match without group, is the value
+	Regex string `yaml:"regex"`
+
+	// JSONPath selects the value in a JSON or YAML file (e.g. $.kubeVersion)
+	JSONPath string `yaml:"jsonPath"`
+
+	// Type is "constraint" (default) when the value is a version range, or
+	// "version" when it is a version
+	Type string `yaml:"type"`
+}
+
+// Extractor value types
+const (
+	ExtractorTypeConstraint = "constraint"
+	ExtractorTypeVersion    = "version"
+)
+
+// HostAuth holds the credentials sent to a host
+type HostAuth struct {
+	// BearerToken is sent as "Authorization: Bearer <token>"
+	BearerToken string `yaml:"bearerToken"`
+
+	// BearerTokenEnv names an environment variable holding the token
+	BearerTokenEnv string `yaml:"bearerTokenEnv"`
+}
+
+// Token returns the bearer token, preferring the environment variable when set
+func (a HostAuth) Token() string {
+	if a.BearerTokenEnv != "" {
+		if token := os.Getenv(a.BearerTokenEnv); token != "" {
+			return token
+		}
+	}
+	return a.BearerToken
+}
+
+// DefaultSettings returns the settings used when no configuration file exists
+func DefaultSettings() Settings {
+	return Settings{
+		Download: DownloadSettings{
+			Retries:         3,
+			RetryBackoff:    time.Second,
+			MaxRetryBackoff: 30 * time.Second,
+			Timeout:         10 * time.Minute,
+		},
+		Timeouts: TimeoutSettings{
+			HTTP:    30 * time.Second,
+			Cluster: 15 * time.Second,
+		},
+		Signature: SignatureSettings{
+			Identity: KubernetesReleaseSigner,
+			Issuer:   KubernetesReleaseSignerIssuer,
+		},
+	}
 }
 
 // New creates a new configuration
@@ -35,17 +253,77 @@ func New() (*Config, error) {
 	kuveDir := filepath.Join(homeDir, "."+AppName)
 	binDir := filepath.Join(kuveDir, "bin")
 	versionsDir := filepath.Join(kuveDir, "versions")
+	platformsDir := filepath.Join(kuveDir, "platforms")
 	currentSymlink := filepath.Join(binDir, KubectlBinaryName)
 
+	configFile := filepath.Join(kuveDir, ConfigFileName)
+	if path := os.Getenv(ConfigFileEnv); path != "" {
+		configFile = path
+	}
+
+	settings, err := LoadSettings(configFile)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Config{
 		HomeDir:        homeDir,
 		KuveDir:        kuveDir,
 		BinDir:         binDir,
 		VersionsDir:    versionsDir,
+		PlatformsDir:   platformsDir,
 		CurrentSymlink: currentSymlink,
+		ConfigFile:     configFile,
+		Settings:       settings,
 	}, nil
 }
 
+// LoadSettings reads the settings from a configuration file.
+// Missing files and missing keys fall back to the default settings.
+func LoadSettings(path string) (Settings, error) {
+	settings := DefaultSettings()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return settings, nil
+		}
+		return settings, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		return settings, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	if _, ok := settings.ToolDefinitions[KubectlBinaryName]; ok {
+		return settings, fmt.Errorf("invalid config file %s: kubectl is built in and cannot be redefined under tools", path)
+	}
+
+	return settings, nil
+}
+
+// PlatformVersionsDir returns the directory holding versions built for another
+// platform, so that they never mix with the versions runnable on this machine
+func (c *Config) PlatformVersionsDir(goos, goarch string) string {
+	return filepath.Join(c.PlatformsDir, goos+"-"+goarch)
+}
+
+// QuarantineDir returns the directory corrupted versions of the managed tool are moved to
+func (c *Config) QuarantineDir() string {
+	return filepath.Join(c.KuveDir, "quarantine", c.Tool().Name)
+}
+
+// IsPinned reports whether an installed version of the managed tool is pinned.
+// Any code path removing installed versions must honour it.
+func (c *Config) IsPinned(version string) bool {
+	_, err := os.Stat(filepath.Join(c.VersionsDir, version, PinFileName))
+	return err == nil
+}
+
+// HistoryFile returns the path of the switch history log, shared by all tools
+func (c *Config) HistoryFile() string {
+	return filepath.Join(c.KuveDir, HistoryFileName)
+}
+
 // EnsureDirectories creates necessary directories if they don't exist
 func (c *Config) EnsureDirectories() error {
 	dirs := []string{c.KuveDir, c.BinDir, c.VersionsDir}
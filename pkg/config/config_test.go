@@ -3,7 +3,9 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func TestNew(t *testing.T) {
@@ -34,6 +36,11 @@ func TestNew(t *testing.T) {
 	if cfg.VersionsDir != expectedVersionsDir {
 		t.Errorf("VersionsDir = %s, want %s", cfg.VersionsDir, expectedVersionsDir)
 	}
+
+	expectedPlatformDir := filepath.Join(cfg.KuveDir, "platforms", "linux-arm64")
+	if got := cfg.PlatformVersionsDir("linux", "arm64"); got != expectedPlatformDir {
+		t.Errorf("PlatformVersionsDir() = %s, want %s", got, expectedPlatformDir)
+	}
 }
 
 func TestEnsureDirectories(t *testing.T) {
@@ -69,3 +76,73 @@ func TestEnsureDirectories(t *testing.T) {
 		}
 	}
 }
+
+func TestIsPinned(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "kuve-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	cfg := &Config{VersionsDir: tmpDir}
+	os.MkdirAll(filepath.Join(tmpDir, "v1.28.0"), 0755)
+	os.MkdirAll(filepath.Join(tmpDir, "v1.29.0"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "v1.28.0", PinFileName), []byte{}, 0644)
+
+	if !cfg.IsPinned("v1.28.0") {
+		t.Error("IsPinned(v1.28.0) = false, want true")
+	}
+	if cfg.IsPinned("v1.29.0") {
+		t.Error("IsPinned(v1.29.0) = true, want false")
+	}
+	if cfg.IsPinned("v1.30.0") {
+		t.Error("IsPinned(v1.30.0) = true, want false for a missing version")
+	}
+}
+
+func TestLoadSettings(t *testing.T) {
+	// Create a temporary directory for testing
+	tmpDir, err := os.MkdirTemp("", "kuve-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// Missing file falls back to defaults
+	settings, err := LoadSettings(filepath.Join(tmpDir, "missing.yaml"))
+	if err != nil {
+		t.Fatalf("LoadSettings() error = %v", err)
+	}
+	if !reflect.DeepEqual(settings, DefaultSettings()) {
+		t.Errorf("LoadSettings() = %+v, want defaults", settings)
+	}
+
+	// Partial file overrides only the given keys
+	configFile := filepath.Join(tmpDir, ConfigFileName)
+	content := "download:\n  retries: 5\n  timeout: 2m\n"
+	if err := os.WriteFile(configFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	settings, err = LoadSettings(configFile)
+	if err != nil {
+		t.Fatalf("LoadSettings() error = %v", err)
+	}
+	if settings.Download.Retries != 5 {
+		t.Errorf("Retries = %d, want 5", settings.Download.Retries)
+	}
+	if settings.Download.Timeout != 2*time.Minute {
+		t.Errorf("Timeout = %s, want 2m", settings.Download.Timeout)
+	}
+	if settings.Download.RetryBackoff != DefaultSettings().Download.RetryBackoff {
+		t.Errorf("RetryBackoff = %s, want default", settings.Download.RetryBackoff)
+	}
+
+	// Invalid file is reported
+	if err := os.WriteFile(configFile, []byte("download: [\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	if _, err := LoadSettings(configFile); err == nil {
+		t.Errorf("Expected LoadSettings() to fail on invalid YAML")
+	}
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// globalKey is the key of the global versions in the configuration file
+const globalKey = "global"
+
+// VersionEnv returns the environment variable overriding the version of the
+// tool, e.g. KUVE_KUBECTL_VERSION
+func (t Tool) VersionEnv() string {
+	name := strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(t.Name))
+	return "KUVE_" + name + "_VERSION"
+}
+
+// GlobalVersion returns the global version of the managed tool, empty when not set
+func (c *Config) GlobalVersion() string {
+	return c.Settings.GlobalVersions[c.Tool().Name]
+}
+
+// SetGlobalVersion persists the global version of the managed tool in the
+// configuration file, keeping the rest of the file as written. An empty
+// version removes the global version, anything else must be a full version.
+func (c *Config) SetGlobalVersion(version string) error {
+	if version != "" {
+		if err := ValidateVersion(version); err != nil {
+			return err
+		}
+	}
+
+	var doc yaml.Node
+	data, err := os.ReadFile(c.ConfigFile)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("failed to parse config file %s: %w", c.ConfigFile, err)
+		}
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("config file %s is not a mapping", c.ConfigFile)
+	}
+
+	global := mappingEntry(root, globalKey)
+	if global == nil {
+		if version == "" {
+			return nil
+		}
+		global = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: globalKey}, global)
+	}
+	if global.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s in config file %s is not a mapping", globalKey, c.ConfigFile)
+	}
+
+	name := c.Tool().Name
+	if value := mappingEntry(global, name); value != nil {
+		if version == "" {
+			removeMappingEntry(global, name)
+		} else {
+			value.Kind, value.Tag, value.Value = yaml.ScalarNode, "!!str", version
+		}
+	} else if version != "" {
+		global.Content = append(global.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: name},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: version})
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return err
+	}
+	encoder.Close()
+
+	if err := os.MkdirAll(filepath.Dir(c.ConfigFile), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(c.ConfigFile, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	if c.Settings.GlobalVersions == nil {
+		c.Settings.GlobalVersions = map[string]string{}
+	}
+	if version == "" {
+		delete(c.Settings.GlobalVersions, name)
+	} else {
+		c.Settings.GlobalVersions[name] = version
+	}
+	return nil
+}
+
+// mappingEntry returns the value of a key of a YAML mapping, nil when missing
+func mappingEntry(mapping *yaml.Node, key string) *yaml.Node {
+	for idx := 0; idx+1 < len(mapping.Content); idx += 2 {
+		if mapping.Content[idx].Value == key {
+			return mapping.Content[idx+1]
+		}
+	}
+	return nil
+}
+
+// removeMappingEntry removes a key of a YAML mapping
+func removeMappingEntry(mapping *yaml.Node, key string) {
+	for idx := 0; idx+1 < len(mapping.Content); idx += 2 {
+		if mapping.Content[idx].Value == key {
+			mapping.Content = append(mapping.Content[:idx], mapping.Content[idx+2:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetGlobalVersion(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "kuve-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	configFile := filepath.Join(tmpDir, ConfigFileName)
+	original := "# Corporate mirror\ndownload:\n  retries: 5\n"
+	if err := os.WriteFile(configFile, []byte(original), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	base := &Config{KuveDir: tmpDir, ConfigFile: configFile, Settings: DefaultSettings()}
+	base.Settings.ToolDefinitions = map[string]Tool{
+		"helm": {VersionFile: ".helm-version", URL: "https://get.helm.sh/helm-{{.Version}}"},
+	}
+	helm, err := base.ForTool("helm")
+	if err != nil {
+		t.Fatalf("ForTool() error = %v", err)
+	}
+
+	if err := base.SetGlobalVersion("v1.28.3"); err != nil {
+		t.Fatalf("SetGlobalVersion() error = %v", err)
+	}
+	if err := helm.SetGlobalVersion("v3.14.0"); err != nil {
+		t.Fatalf("SetGlobalVersion() error = %v", err)
+	}
+	if err := base.SetGlobalVersion("v1.29.0"); err != nil {
+		t.Fatalf("SetGlobalVersion() error = %v", err)
+	}
+
+	data, _ := os.ReadFile(configFile)
+	if !strings.Contains(string(data), "# Corporate mirror") {
+		t.Errorf("Expected the comments of the config file to be kept:\n%s", data)
+	}
+
+	settings, err := LoadSettings(configFile)
+	if err != nil {
+		t.Fatalf("LoadSettings() error = %v", err)
+	}
+	if settings.Download.Retries != 5 {
+		t.Errorf("Download.Retries = %d, want 5", settings.Download.Retries)
+	}
+	if got := settings.GlobalVersions["kubectl"]; got != "v1.29.0" {
+		t.Errorf("kubectl global version = %q, want v1.29.0", got)
+	}
+	if got := settings.GlobalVersions["helm"]; got != "v3.14.0" {
+		t.Errorf("helm global version = %q, want v3.14.0", got)
+	}
+	if got := base.GlobalVersion(); got != "v1.29.0" {
+		t.Errorf("GlobalVersion() = %q, want v1.29.0", got)
+	}
+
+	if err := base.SetGlobalVersion(""); err != nil {
+		t.Fatalf("SetGlobalVersion() to unset error = %v", err)
+	}
+	settings, _ = LoadSettings(configFile)
+	if _, ok := settings.GlobalVersions["kubectl"]; ok || base.GlobalVersion() != "" {
+		t.Errorf("Expected the kubectl global version to be removed")
+	}
+}
+
+func TestSetGlobalVersionInvalid(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "kuve-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	cfg := &Config{KuveDir: tmpDir, ConfigFile: filepath.Join(tmpDir, ConfigFileName), Settings: DefaultSettings()}
+	for _, version := range []string{"vfoo", "v1.29", "latest", "v1.29.3/../../evil"} {
+		if err := cfg.SetGlobalVersion(version); err == nil {
+			t.Errorf("SetGlobalVersion(%q) expected an error", version)
+		}
+	}
+	if _, err := os.Stat(cfg.ConfigFile); !os.IsNotExist(err) {
+		t.Errorf("Expected no config file to be written for invalid versions")
+	}
+}
+
+func TestVersionEnv(t *testing.T) {
+	tests := map[string]string{
+		"kubectl":       "KUVE_KUBECTL_VERSION",
+		"helm":          "KUVE_HELM_VERSION",
+		"kube-score.io": "KUVE_KUBE_SCORE_IO_VERSION",
+	}
+	for name, want := range tests {
+		if got := (Tool{Name: name}).VersionEnv(); got != want {
+			t.Errorf("VersionEnv() of %s = %s, want %s", name, got, want)
+		}
+	}
+}
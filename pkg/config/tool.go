@@ -0,0 +1,258 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+const (
+	// DefaultToolName is the tool managed when no other tool is selected
+	DefaultToolName = KubectlBinaryName
+
+	// ArchiveFormatTarGz is a tool released as a gzipped tarball
+	ArchiveFormatTarGz = "tar.gz"
+)
+
+// fullVersionRegex matches the installable versions of the tools (v1.28.3)
+var fullVersionRegex = regexp.MustCompile(`^v\d+\.\d+\.\d+$`)
+
+// IsFullVersion reports whether a version is a full version with the 'v' prefix
+func IsFullVersion(version string) bool {
+	return fullVersionRegex.MatchString(version)
+}
+
+// ValidateVersion checks that a version can name a directory of the versions
+// directory. It returns an error for anything but a full version (v1.28.3).
+func ValidateVersion(version string) error {
+	if !IsFullVersion(version) {
+		return fmt.Errorf("invalid version %q, expected a full version like v1.28.3", version)
+	}
+	return nil
+}
+
+// Tool describes a command line tool whose versions are managed by kuve.
+//
+// URL templates are Go templates receiving ToolURLData, for example
+// https://get.helm.sh/helm-{{.Version}}-{{.OS}}-{{.Arch}}.tar.gz
+type Tool struct {
+	// Name identifies the tool on the command line (--tool)
+	Name string `yaml:"-"`
+
+	// Binary is the name of the executable (defaults to Name)
+	Binary string `yaml:"binary"`
+
+	// VersionFile is the per-project file pinning the tool version
+	VersionFile string `yaml:"versionFile"`
+
+	// StableURL returns the latest stable version as plain text
+	StableURL string `yaml:"stableURL"`
+
+	// MinorStableURL returns the latest patch of a minor version as plain text.
+	// When empty, minor versions are resolved from the GitHub releases.
+	MinorStableURL string `yaml:"minorStableURL"`
+
+	// GitHubRepo is the owner/name repository whose releases list the remote versions
+	GitHubRepo string `yaml:"githubRepo"`
+
+	// TagPrefix is the prefix of release tags in front of the version (e.g. "kustomize/")
+	TagPrefix string `yaml:"tagPrefix"`
+
+	// URL is the template of the download URL
+	URL string `yaml:"url"`
+
+	// Archive is the format of the downloaded artifact: empty for a bare binary, or "tar.gz"
+	Archive string `yaml:"archive"`
+
+	// ChecksumURL is the template of the URL of the SHA-256 checksum, either a
+	// bare checksum or a sha256sum style list. No verification when empty.
+	ChecksumURL string `yaml:"checksumURL"`
+
+	// VersionArgs are the arguments printing the tool version, used as smoke
+	// test by 'kuve verify' (defaults to "version")
+	VersionArgs []string `yaml:"versionArgs"`
+}
+
+// ToolURLData holds the values available to the URL templates of a tool
+type ToolURLData struct {
+	// Version is the version with its "v" prefix (v1.28.3)
+	Version string
+
+	// VersionNumber is the version without its "v" prefix (1.28.3)
+	VersionNumber string
+
+	// Tag is the release tag (TagPrefix followed by Version)
+	Tag string
+
+	// Major and Minor are the first version components (for MinorStableURL)
+	Major string
+	Minor string
+
+	OS   string
+	Arch string
+
+	// Binary is the executable name, including the .exe extension on Windows
+	Binary string
+
+	// Ext is ".exe" on Windows and empty otherwise
+	Ext string
+}
+
+// KubectlTool returns the built-in kubectl definition
+func KubectlTool() Tool {
+	return Tool{
+		Name:           KubectlBinaryName,
+		Binary:         KubectlBinaryName,
+		VersionFile:    VersionFileName,
+		StableURL:      "https://dl.k8s.io/release/stable.txt",
+		MinorStableURL: "https://dl.k8s.io/release/stable-{{.Major}}.{{.Minor}}.txt",
+		GitHubRepo:     "kubernetes/kubernetes",
+		URL:            "https://dl.k8s.io/release/{{.Version}}/bin/{{.OS}}/{{.Arch}}/{{.Binary}}",
+		ChecksumURL:    "https://dl.k8s.io/release/{{.Version}}/bin/{{.OS}}/{{.Arch}}/{{.Binary}}.sha256",
+	}
+}
+
+// IsKubectl reports whether the tool is the built-in kubectl
+func (t Tool) IsKubectl() bool {
+	return t.Name == KubectlBinaryName
+}
+
+// BinaryName returns the name of the executable
+func (t Tool) BinaryName() string {
+	if t.Binary != "" {
+		return t.Binary
+	}
+	return t.Name
+}
+
+// isFileName reports whether a name is a single path element, so that it
+// cannot point outside the directory it is joined to
+func isFileName(name string) bool {
+	return name != "" && !strings.ContainsAny(name, `/\`) && name != "." && name != ".."
+}
+
+// Validate checks that the definition has what is needed to install the tool
+func (t Tool) Validate() error {
+	if !isFileName(t.Name) {
+		return fmt.Errorf("invalid tool name %q", t.Name)
+	}
+	// The binary names the link in the bin directory
+	if t.Binary != "" && !isFileName(t.Binary) {
+		return fmt.Errorf("tool %s: invalid binary name %q", t.Name, t.Binary)
+	}
+	if !t.IsKubectl() && t.BinaryName() == KubectlBinaryName {
+		return fmt.Errorf("tool %s: binary name %q is reserved for the built-in kubectl", t.Name, t.BinaryName())
+	}
+	if t.URL == "" {
+		return fmt.Errorf("tool %s: url is required", t.Name)
+	}
+	if t.VersionFile == "" {
+		return fmt.Errorf("tool %s: versionFile is required", t.Name)
+	}
+	if t.Archive != "" && t.Archive != ArchiveFormatTarGz {
+		return fmt.Errorf("tool %s: unsupported archive format %q", t.Name, t.Archive)
+	}
+	for _, tmpl := range []string{t.URL, t.ChecksumURL, t.MinorStableURL} {
+		if _, err := template.New(t.Name).Option("missingkey=error").Parse(tmpl); err != nil {
+			return fmt.Errorf("tool %s: invalid template: %w", t.Name, err)
+		}
+	}
+	return nil
+}
+
+// ExpandURL renders a URL template of the tool
+func (t Tool) ExpandURL(tmpl string, data ToolURLData) (string, error) {
+	parsed, err := template.New(t.Name).Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL template for %s: %w", t.Name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := parsed.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("invalid URL template for %s: %w", t.Name, err)
+	}
+	return buf.String(), nil
+}
+
+// URLData returns the template values of a version and platform
+func (t Tool) URLData(version, goos, goarch string) ToolURLData {
+	data := ToolURLData{
+		Version:       version,
+		VersionNumber: strings.TrimPrefix(version, "v"),
+		Tag:           t.TagPrefix + version,
+		OS:            goos,
+		Arch:          goarch,
+		Binary:        t.BinaryName(),
+	}
+	if goos == "windows" {
+		data.Ext = ".exe"
+		data.Binary += data.Ext
+	}
+	return data
+}
+
+// Tools returns the managed tools by name: the built-in kubectl and the tools
+// defined in the settings. A definition named kubectl never replaces the
+// built-in one (LoadSettings rejects it).
+func (s Settings) Tools() map[string]Tool {
+	tools := map[string]Tool{DefaultToolName: KubectlTool()}
+	for name, tool := range s.ToolDefinitions {
+		if name == KubectlBinaryName {
+			continue
+		}
+		tool.Name = name
+		tools[name] = tool
+	}
+	return tools
+}
+
+// ToolNames returns the sorted names of the managed tools
+func (s Settings) ToolNames() []string {
+	var names []string
+	for name := range s.Tools() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Tool returns the tool the configuration manages, kubectl by default
+func (c *Config) Tool() Tool {
+	if c.tool != nil {
+		return *c.tool
+	}
+	return KubectlTool()
+}
+
+// ForTool returns a copy of the configuration managing the named tool.
+// kubectl keeps the historical layout; other tools are stored under
+// ~/.kuve/tools/<name>/ and linked into the same bin directory.
+func (c *Config) ForTool(name string) (*Config, error) {
+	if name == "" {
+		name = DefaultToolName
+	}
+
+	tool, ok := c.Settings.Tools()[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown tool %q, available: %s", name, strings.Join(c.Settings.ToolNames(), ", "))
+	}
+	if err := tool.Validate(); err != nil {
+		return nil, err
+	}
+
+	toolCfg := *c
+	toolCfg.tool = &tool
+	if tool.IsKubectl() {
+		return &toolCfg, nil
+	}
+
+	toolDir := filepath.Join(c.KuveDir, "tools", name)
+	toolCfg.VersionsDir = filepath.Join(toolDir, "versions")
+	toolCfg.PlatformsDir = filepath.Join(toolDir, "platforms")
+	toolCfg.CurrentSymlink = filepath.Join(c.BinDir, tool.BinaryName())
+	return &toolCfg, nil
+}
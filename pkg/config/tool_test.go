@@ -0,0 +1,159 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestForTool(t *testing.T) {
+	// Create a temporary directory for testing
+	tmpDir, err := os.MkdirTemp("", "kuve-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	configFile := filepath.Join(tmpDir, ConfigFileName)
+	content := `tools:
+  kustomize:
+    versionFile: .kustomize-version
+    githubRepo: kubernetes-sigs/kustomize
+    tagPrefix: kustomize/
+    url: https://github.com/kubernetes-sigs/kustomize/releases/download/{{.Tag}}/kustomize_{{.Version}}_{{.OS}}_{{.Arch}}.tar.gz
+    archive: tar.gz
+    checksumURL: https://github.com/kubernetes-sigs/kustomize/releases/download/{{.Tag}}/checksums.txt
+  broken:
+    versionFile: .broken-version
+`
+	if err := os.WriteFile(configFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	settings, err := LoadSettings(configFile)
+	if err != nil {
+		t.Fatalf("LoadSettings() error = %v", err)
+	}
+
+	cfg := &Config{
+		KuveDir:        tmpDir,
+		BinDir:         filepath.Join(tmpDir, "bin"),
+		VersionsDir:    filepath.Join(tmpDir, "versions"),
+		PlatformsDir:   filepath.Join(tmpDir, "platforms"),
+		CurrentSymlink: filepath.Join(tmpDir, "bin", KubectlBinaryName),
+		Settings:       settings,
+	}
+
+	// kubectl is the default and keeps the historical layout
+	if cfg.Tool().Name != KubectlBinaryName {
+		t.Errorf("Tool() = %s, want kubectl", cfg.Tool().Name)
+	}
+	kubectlCfg, err := cfg.ForTool("")
+	if err != nil {
+		t.Fatalf("ForTool(\"\") error = %v", err)
+	}
+	if kubectlCfg.VersionsDir != cfg.VersionsDir || kubectlCfg.CurrentSymlink != cfg.CurrentSymlink {
+		t.Errorf("ForTool(\"\") changed the kubectl layout")
+	}
+
+	kustomizeCfg, err := cfg.ForTool("kustomize")
+	if err != nil {
+		t.Fatalf("ForTool(kustomize) error = %v", err)
+	}
+	if got, want := kustomizeCfg.VersionsDir, filepath.Join(tmpDir, "tools", "kustomize", "versions"); got != want {
+		t.Errorf("VersionsDir = %s, want %s", got, want)
+	}
+	if got, want := kustomizeCfg.CurrentSymlink, filepath.Join(tmpDir, "bin", "kustomize"); got != want {
+		t.Errorf("CurrentSymlink = %s, want %s", got, want)
+	}
+	if cfg.Tool().Name != KubectlBinaryName {
+		t.Errorf("ForTool() modified the original configuration")
+	}
+
+	tool := kustomizeCfg.Tool()
+	url, err := tool.ExpandURL(tool.URL, tool.URLData("v5.4.1", "linux", "amd64"))
+	if err != nil {
+		t.Fatalf("ExpandURL() error = %v", err)
+	}
+	want := "https://github.com/kubernetes-sigs/kustomize/releases/download/kustomize/v5.4.1/kustomize_v5.4.1_linux_amd64.tar.gz"
+	if url != want {
+		t.Errorf("ExpandURL() = %s, want %s", url, want)
+	}
+
+	if _, err := cfg.ForTool("broken"); err == nil {
+		t.Errorf("Expected ForTool() to reject a tool without url")
+	}
+	if _, err := cfg.ForTool("helm"); err == nil {
+		t.Errorf("Expected ForTool() to reject an unknown tool")
+	}
+}
+
+func TestToolURLData(t *testing.T) {
+	tool := KubectlTool()
+
+	data := tool.URLData("v1.28.3", "windows", "amd64")
+	if data.VersionNumber != "1.28.3" || data.Binary != "kubectl.exe" || data.Ext != ".exe" {
+		t.Errorf("URLData() = %+v", data)
+	}
+
+	url, err := tool.ExpandURL(tool.URL, data)
+	if err != nil {
+		t.Fatalf("ExpandURL() error = %v", err)
+	}
+	if want := "https://dl.k8s.io/release/v1.28.3/bin/windows/amd64/kubectl.exe"; url != want {
+		t.Errorf("ExpandURL() = %s, want %s", url, want)
+	}
+
+	if _, err := tool.ExpandURL("{{.Missing}}", data); err == nil {
+		t.Errorf("Expected ExpandURL() to fail on an unknown field")
+	}
+}
+
+func TestToolValidateBinary(t *testing.T) {
+	base := Tool{Name: "helm", VersionFile: ".helm-version", URL: "https://example.com/{{.Version}}"}
+	if err := base.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	for _, binary := range []string{"../../.bashrc", "bin/helm", "..", KubectlBinaryName} {
+		tool := base
+		tool.Binary = binary
+		if err := tool.Validate(); err == nil {
+			t.Errorf("Validate() accepted binary %q", binary)
+		}
+	}
+}
+
+func TestToolsKeepBuiltinKubectl(t *testing.T) {
+	settings := Settings{ToolDefinitions: map[string]Tool{
+		KubectlBinaryName: {VersionFile: ".evil-version", URL: "https://example.com/{{.Version}}"},
+	}}
+	if got := settings.Tools()[KubectlBinaryName]; got.URL != KubectlTool().URL {
+		t.Errorf("Tools() replaced the built-in kubectl with %+v", got)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "kuve-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	configFile := filepath.Join(tmpDir, ConfigFileName)
+	os.WriteFile(configFile, []byte("tools:\n  kubectl:\n    url: https://example.com/{{.Version}}\n"), 0644)
+	if _, err := LoadSettings(configFile); err == nil {
+		t.Errorf("Expected LoadSettings() to reject a kubectl tool definition")
+	}
+}
+
+func TestValidateVersion(t *testing.T) {
+	for _, version := range []string{"v1.28.3", "v3.14.0", "v0.1.10"} {
+		if err := ValidateVersion(version); err != nil {
+			t.Errorf("ValidateVersion(%q) error = %v", version, err)
+		}
+	}
+	for _, version := range []string{"", "1.28.3", "v1.28", "vlatest", "v1.28.3-dirty", "v1/../../x", "../v1.28.3"} {
+		if err := ValidateVersion(version); err == nil {
+			t.Errorf("ValidateVersion(%q) succeeded, want an error", version)
+		}
+	}
+}